@@ -0,0 +1,91 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+var gUpdate = flag.Bool("update", false, "update golden files") // nolint: gochecknoglobals
+
+func TestScan(test *testing.T) {
+	entries, warnings, err := scan(filepath.Join("testdata", "app"))
+
+	assert.NoError(test, err)
+	assert.Equal(test, []string{
+		"store/store.go:55: message is not a constant",
+		"store/store.go:69: message is not a constant",
+	}, warnings)
+	assert.Len(test, entries, 11)
+
+	for _, entry := range entries {
+		assert.Equal(test, rterror.FingerprintOf(entry.Function, entry.Message), entry.ID)
+	}
+}
+
+func TestScanFunctions(test *testing.T) {
+	entries, _, err := scan(filepath.Join("testdata", "app"))
+
+	assert.NoError(test, err)
+
+	functions := make(map[string]string)
+
+	for _, entry := range entries {
+		functions[entry.Message] = entry.Function
+	}
+
+	assert.Equal(test, map[string]string{
+		"usage: app <name>":         "main.init",
+		"first init":                "main.init.0",
+		"second | init":             "main.init.1",
+		"cannot run {p0}":           "main.main.func1",
+		"invalid key `{p0}`":        "example.com/app/store.(*Store).Get",
+		"store: key {p0} not found": "example.com/app/store.(*Store).Get",
+		"cannot close store":        "example.com/app/store.Store.Close",
+		"cache miss":                "example.com/app/store.Cache[...].Get",
+		"not found":                 "example.com/app/store.Find[...]",
+		"empty key":                 "example.com/app/store.(*Store).Put",
+	}, functions)
+}
+
+func TestCatalogFormats(test *testing.T) {
+	entries, _, err := scan(filepath.Join("testdata", "app"))
+
+	assert.NoError(test, err)
+
+	for format, write := range gWriters {
+		var buffer bytes.Buffer
+
+		assert.NoError(test, write(&buffer, entries))
+
+		golden := filepath.Join("testdata", "catalog."+format)
+
+		if *gUpdate {
+			assert.NoError(test, os.WriteFile(golden, buffer.Bytes(), 0o600))
+		}
+
+		expected, err := os.ReadFile(golden)
+
+		assert.NoError(test, err)
+		assert.Equal(test, string(expected), buffer.String(), format)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command rterror-catalog generates catalog of all error messages that can be
+// emitted by rterror calls from module packages. It scans the New(), NewCtx(),
+// NewSkipCaller() and NewCtxSkipCaller() calls with message templates defined
+// as string literals or as constants, together with kinds set by the chained
+// SetKind() method. Catalog entry ID is equal to the runtime error fingerprint,
+// so logged errors can be matched with catalog entries. It is exact for errors
+// not created by wrapper functions that skip callers.
+//
+//  rterror-catalog -format markdown -o ERRORS.md .
+//
+// Supported formats are markdown, json and csv.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// gWriters defines supported catalog formats.
+var gWriters = map[string]func(io.Writer, []*Entry) error{ // nolint: gochecknoglobals
+	"markdown": writeMarkdown,
+	"json":     writeJSON,
+	"csv":      writeCSV,
+}
+
+func main() {
+	format := flag.String("format", "markdown", "catalog format: markdown, json or csv")
+	output := flag.String("o", "", "write catalog to file instead of standard output")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: rterror-catalog [flags] [module directory]\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	rterror.Main(func() error {
+		return run(*format, *output, flag.Arg(0))
+	})
+}
+
+func run(format, output, root string) error {
+	write, ok := gWriters[format]
+
+	if !ok {
+		return fmt.Errorf("unsupported format %q", format)
+	}
+
+	if root == "" {
+		root = "."
+	}
+
+	entries, warnings, err := scan(root)
+
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	if output == "" {
+		return write(os.Stdout, entries)
+	}
+
+	var buffer bytes.Buffer
+
+	if err := write(&buffer, entries); err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, buffer.Bytes(), 0o644) // nolint: gosec
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// writeMarkdown writes catalog as Markdown document with a table per package.
+func writeMarkdown(writer io.Writer, entries []*Entry) error {
+	var builder strings.Builder
+
+	builder.WriteString("# Error catalog\n")
+
+	for index, entry := range entries {
+		if (index == 0) || (entries[index-1].Package != entry.Package) {
+			builder.WriteString("\n## " + entry.Package + "\n\n")
+			builder.WriteString("| ID | Message | Kind | Function | Locations |\n")
+			builder.WriteString("|----|---------|------|----------|-----------|\n")
+		}
+
+		builder.WriteString("| `" + entry.ID + "` | " + code(entry.Message) + " | " + code(entry.Kind) + " | " +
+			code(functionBase(entry)) + " | " +
+			cell(strings.Join(entry.Locations, "<br>")) + " |\n")
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
+}
+
+// writeJSON writes catalog as JSON array.
+func writeJSON(writer io.Writer, entries []*Entry) error {
+	if entries == nil {
+		entries = []*Entry{}
+	}
+
+	encoder := json.NewEncoder(writer)
+
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(entries)
+}
+
+// writeCSV writes catalog as CSV with header. Locations are separated by space.
+func writeCSV(writer io.Writer, entries []*Entry) error {
+	w := csv.NewWriter(writer)
+
+	if err := w.Write([]string{"id", "message", "kind", "package", "function", "locations"}); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := w.Write([]string{
+			entry.ID,
+			entry.Message,
+			entry.Kind,
+			entry.Package,
+			entry.Function,
+			strings.Join(entry.Locations, " "),
+		}); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// code returns Markdown code span with value escaped for table cell.
+func code(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if strings.Contains(value, "`") {
+		return "`` " + cell(value) + " ``"
+	}
+
+	return "`" + cell(value) + "`"
+}
+
+func cell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(value)
+}
+
+// functionBase returns function name without package path.
+func functionBase(entry *Entry) string {
+	if function := strings.TrimPrefix(entry.Function, entry.Package+"."); function != entry.Function {
+		return function
+	}
+
+	return strings.TrimPrefix(entry.Function, "main.")
+}
@@ -0,0 +1,543 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// rterrorPath defines import path of the rterror package.
+const rterrorPath = "gitlab.com/tymonx/go-error/rterror"
+
+// maxDepth defines maximum depth of constant references.
+const maxDepth = 32
+
+// gConstructors defines position of message argument of rterror constructors.
+var gConstructors = map[string]int{ // nolint: gochecknoglobals
+	"New":              0,
+	"NewCtx":           1,
+	"NewSkipCaller":    1,
+	"NewCtxSkipCaller": 2,
+}
+
+// Entry defines catalog entry. It is a single error message template that
+// can be emitted from function. Entry ID is equal to runtime error fingerprint.
+type Entry struct {
+	ID        string   `json:"id"`
+	Message   string   `json:"message"`
+	Kind      string   `json:"kind,omitempty"`
+	Package   string   `json:"package"`
+	Function  string   `json:"function"`
+	Locations []string `json:"locations"`
+}
+
+type constant struct {
+	value ast.Expr
+	file  *file
+}
+
+type file struct {
+	name    string
+	ast     *ast.File
+	imports map[string]string
+}
+
+type pkg struct {
+	path      string
+	prefix    string
+	files     []*file
+	constants map[string]constant
+	types     map[string]bool
+	closures  map[string]int
+	inits     int
+}
+
+type scanner struct {
+	fset     *token.FileSet
+	root     string
+	packages map[string]*pkg
+	entries  map[string]*Entry
+	kinds    map[*ast.CallExpr]ast.Expr
+	warnings []string
+}
+
+// scan scans all packages from module root directory for rterror calls.
+// Directories vendor, testdata and hidden ones and test files are skipped.
+// It returns catalog entries sorted by package and location and warnings
+// about calls with message that is not a constant.
+func scan(root string) (entries []*Entry, warnings []string, err error) {
+	s := &scanner{
+		fset:     token.NewFileSet(),
+		root:     root,
+		packages: make(map[string]*pkg),
+		entries:  make(map[string]*Entry),
+		kinds:    make(map[*ast.CallExpr]ast.Expr),
+	}
+
+	module, err := modulePath(root)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.parse(module); err != nil {
+		return nil, nil, err
+	}
+
+	paths := make([]string, 0, len(s.packages))
+
+	for p := range s.packages {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		s.scanPackage(s.packages[p])
+	}
+
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Package != entries[j].Package {
+			return entries[i].Package < entries[j].Package
+		}
+
+		return location(entries[i].Locations[0]).less(location(entries[j].Locations[0]))
+	})
+
+	return entries, s.warnings, nil
+}
+
+// modulePath returns module path from the go.mod file or empty string.
+func modulePath(root string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); (len(fields) >= 2) && (fields[0] == "module") {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s *scanner) parse(module string) error {
+	return filepath.WalkDir(s.root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		base := entry.Name()
+
+		if entry.IsDir() {
+			if (name != s.root) && ((base == "vendor") || (base == "testdata") ||
+				strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(base, ".go") || strings.HasSuffix(base, "_test.go") {
+			return nil
+		}
+
+		return s.parseFile(module, name)
+	})
+}
+
+func (s *scanner) parseFile(module, name string) error {
+	f, err := parser.ParseFile(s.fset, name, nil, 0)
+
+	if err != nil {
+		return err
+	}
+
+	relative, err := filepath.Rel(s.root, filepath.Dir(name))
+
+	if err != nil {
+		return err
+	}
+
+	importPath := path.Join(module, filepath.ToSlash(relative))
+
+	p, ok := s.packages[importPath]
+
+	if !ok {
+		p = &pkg{
+			path:      importPath,
+			prefix:    importPath,
+			constants: make(map[string]constant),
+			types:     make(map[string]bool),
+			closures:  make(map[string]int),
+		}
+
+		s.packages[importPath] = p
+	}
+
+	if f.Name.Name == "main" {
+		p.prefix = "main"
+	}
+
+	relativeName, _ := filepath.Rel(s.root, name)
+
+	current := &file{
+		name:    filepath.ToSlash(relativeName),
+		ast:     f,
+		imports: make(map[string]string),
+	}
+
+	for _, spec := range f.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		local := path.Base(importPath)
+
+		if spec.Name != nil {
+			local = spec.Name.Name
+		}
+
+		current.imports[local] = importPath
+	}
+
+	p.files = append(p.files, current)
+
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+
+		if !ok {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			switch value := spec.(type) {
+			case *ast.ValueSpec:
+				for index, ident := range value.Names {
+					if (gen.Tok == token.CONST) && (index < len(value.Values)) {
+						p.constants[ident.Name] = constant{value: value.Values[index], file: current}
+					}
+				}
+			case *ast.TypeSpec:
+				p.types[value.Name.Name] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *scanner) scanPackage(p *pkg) {
+	for _, f := range p.files {
+		for _, decl := range f.ast.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				name := functionName(d)
+
+				if (d.Recv == nil) && (name == "init") {
+					name += "." + strconv.Itoa(p.inits)
+					p.inits++
+				}
+
+				if d.Body != nil {
+					s.inspect(p, f, d.Body, p.prefix+"."+name)
+				}
+			case *ast.GenDecl:
+				s.inspect(p, f, d, p.prefix+".init")
+			}
+		}
+	}
+}
+
+// inspect inspects node from function. Function literals are named like
+// by the Go runtime, with the funcN suffix numbered in order of appearance.
+// Package level function literals and calls belong to the init function.
+func (s *scanner) inspect(p *pkg, f *file, node ast.Node, function string) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			p.closures[function]++
+			s.inspect(p, f, n.Body, function+".func"+strconv.Itoa(p.closures[function]))
+
+			return false
+		case *ast.CallExpr:
+			s.call(p, f, n, function)
+		}
+
+		return true
+	})
+}
+
+func (s *scanner) call(p *pkg, f *file, call *ast.CallExpr, function string) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+
+	if !ok {
+		return
+	}
+
+	if selector.Sel.Name == "SetKind" {
+		if constructor := s.constructor(f, selector.X); (constructor != nil) && (len(call.Args) == 1) {
+			s.kinds[constructor] = call.Args[0]
+		}
+
+		return
+	}
+
+	if !s.isConstructor(f, call) {
+		return
+	}
+
+	position := s.fset.Position(call.Pos())
+	argument := call.Args[gConstructors[selector.Sel.Name]]
+	message, ok := s.evaluate(p, f, argument, 0)
+
+	if !ok {
+		s.warnings = append(s.warnings, f.name+":"+strconv.Itoa(position.Line)+": message is not a constant")
+		return
+	}
+
+	kind := ""
+
+	if expression, ok := s.kinds[call]; ok {
+		kind, _ = s.evaluate(p, f, expression, 0)
+	}
+
+	s.add(&Entry{
+		ID:        rterror.FingerprintOf(function, message),
+		Message:   message,
+		Kind:      kind,
+		Package:   p.path,
+		Function:  function,
+		Locations: []string{f.name + ":" + strconv.Itoa(position.Line)},
+	})
+}
+
+// add adds entry to catalog. Entries with the same ID are merged.
+func (s *scanner) add(entry *Entry) {
+	existing, ok := s.entries[entry.ID]
+
+	if !ok {
+		s.entries[entry.ID] = entry
+		return
+	}
+
+	existing.Locations = append(existing.Locations, entry.Locations...)
+
+	if existing.Kind == "" {
+		existing.Kind = entry.Kind
+	}
+}
+
+// constructor returns rterror constructor call from chain of method calls.
+func (s *scanner) constructor(f *file, expression ast.Expr) *ast.CallExpr {
+	for {
+		call, ok := expression.(*ast.CallExpr)
+
+		if !ok {
+			return nil
+		}
+
+		if s.isConstructor(f, call) {
+			return call
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+
+		if !ok {
+			return nil
+		}
+
+		expression = selector.X
+	}
+}
+
+func (s *scanner) isConstructor(f *file, call *ast.CallExpr) bool {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+
+	if !ok {
+		return false
+	}
+
+	ident, ok := selector.X.(*ast.Ident)
+
+	if !ok || (f.imports[ident.Name] != rterrorPath) {
+		return false
+	}
+
+	position, ok := gConstructors[selector.Sel.Name]
+
+	return ok && (position < len(call.Args))
+}
+
+// evaluate evaluates string constant expression. It supports string literals,
+// constants from scanned packages, conversions to string, rterror.Kind and types
+// from scanned packages and concatenations.
+func (s *scanner) evaluate(p *pkg, f *file, expression ast.Expr, depth int) (string, bool) {
+	if depth > maxDepth {
+		return "", false
+	}
+
+	switch e := expression.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.STRING {
+			value, err := strconv.Unquote(e.Value)
+			return value, err == nil
+		}
+	case *ast.ParenExpr:
+		return s.evaluate(p, f, e.X, depth+1)
+	case *ast.Ident:
+		if c, ok := p.constants[e.Name]; ok {
+			return s.evaluate(p, c.file, c.value, depth+1)
+		}
+	case *ast.SelectorExpr:
+		if ident, ok := e.X.(*ast.Ident); ok {
+			if imported, ok := s.packages[f.imports[ident.Name]]; ok {
+				if c, ok := imported.constants[e.Sel.Name]; ok {
+					return s.evaluate(imported, c.file, c.value, depth+1)
+				}
+			}
+		}
+	case *ast.CallExpr:
+		if (len(e.Args) == 1) && !e.Ellipsis.IsValid() && s.isType(p, f, e.Fun) {
+			return s.evaluate(p, f, e.Args[0], depth+1)
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			x, ok := s.evaluate(p, f, e.X, depth+1)
+
+			if !ok {
+				return "", false
+			}
+
+			y, ok := s.evaluate(p, f, e.Y, depth+1)
+
+			return x + y, ok
+		}
+	}
+
+	return "", false
+}
+
+// isType returns true if expression is the string type, the rterror.Kind type
+// or a type declared in scanned package. Calls of other functions are not
+// conversions, even with single argument.
+func (s *scanner) isType(p *pkg, f *file, expression ast.Expr) bool {
+	switch e := expression.(type) {
+	case *ast.ParenExpr:
+		return s.isType(p, f, e.X)
+	case *ast.Ident:
+		return (e.Name == "string") || p.types[e.Name]
+	case *ast.SelectorExpr:
+		ident, ok := e.X.(*ast.Ident)
+
+		if !ok {
+			return false
+		}
+
+		if path := f.imports[ident.Name]; path == rterrorPath {
+			return e.Sel.Name == "Kind"
+		} else if imported, ok := s.packages[path]; ok {
+			return imported.types[e.Sel.Name]
+		}
+	}
+
+	return false
+}
+
+// functionName returns function name like returned by the Go runtime without package path.
+func functionName(decl *ast.FuncDecl) string {
+	name := decl.Name.Name
+
+	if (decl.Type.TypeParams != nil) && (len(decl.Type.TypeParams.List) != 0) {
+		name += "[...]"
+	}
+
+	if (decl.Recv == nil) || (len(decl.Recv.List) == 0) {
+		return name
+	}
+
+	receiver := decl.Recv.List[0].Type
+	pointer := false
+
+	if star, ok := receiver.(*ast.StarExpr); ok {
+		receiver = star.X
+		pointer = true
+	}
+
+	typeName := ""
+
+	switch r := receiver.(type) {
+	case *ast.Ident:
+		typeName = r.Name
+	case *ast.IndexExpr:
+		typeName = exprName(r.X) + "[...]"
+	case *ast.IndexListExpr:
+		typeName = exprName(r.X) + "[...]"
+	}
+
+	if pointer {
+		typeName = "(*" + typeName + ")"
+	}
+
+	return typeName + "." + decl.Name.Name
+}
+
+func exprName(expression ast.Expr) string {
+	if ident, ok := expression.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+type location string
+
+// less compares locations by file name and by line number.
+func (l location) less(other location) bool {
+	name, line := l.split()
+	otherName, otherLine := other.split()
+
+	if name != otherName {
+		return name < otherName
+	}
+
+	return line < otherLine
+}
+
+func (l location) split() (name string, line int) {
+	index := strings.LastIndexByte(string(l), ':')
+	line, _ = strconv.Atoi(string(l[index+1:]))
+
+	return string(l[:index]), line
+}
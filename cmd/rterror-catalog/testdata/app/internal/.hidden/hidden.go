@@ -0,0 +1,5 @@
+package hidden
+
+import "gitlab.com/tymonx/go-error/rterror"
+
+var errHidden = rterror.New("hidden")
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/app/store"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+var errUsage = rterror.New("usage: app <name>")
+
+func init() {
+	_ = rterror.New("first init")
+}
+
+func init() {
+	_ = rterror.New("second | init")
+}
+
+func main() {
+	run := func(name string) error {
+		if name == "" {
+			return errUsage
+		}
+
+		return rterror.New("cannot run {p0}", name).Wrap(store.New().Get(name))
+	}
+
+	fmt.Println(run(""))
+}
@@ -0,0 +1,6 @@
+package messages
+
+const (
+	Prefix   = "store: "
+	NotFound = Prefix + "key {p0} not found"
+)
@@ -0,0 +1,8 @@
+package store
+
+import "gitlab.com/tymonx/go-error/rterror"
+
+const (
+	KindNotFound rterror.Kind = "not_found"
+	KindInternal              = rterror.Kind("internal")
+)
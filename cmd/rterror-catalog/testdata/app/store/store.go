@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+
+	"example.com/app/messages"
+	rt "gitlab.com/tymonx/go-error/rterror"
+)
+
+const invalid = "invalid key `{p0}`"
+
+type Store struct {
+	values map[string]string
+}
+
+type Cache[T any] struct{}
+
+func New() *Store {
+	return &Store{}
+}
+
+func (s *Store) Get(key string) error {
+	if key == "" {
+		return rt.New(invalid, key)
+	}
+
+	if _, ok := s.values[key]; !ok {
+		return rt.New(messages.NotFound, key).SetKind(KindNotFound)
+	}
+
+	return nil
+}
+
+func (s Store) Close(ctx context.Context) error {
+	return rt.NewCtx(ctx, "cannot close store").SetField("a", 1).SetKind(KindInternal)
+}
+
+func (Cache[T]) Get() error {
+	return rt.New("cache miss")
+}
+
+func Find[T any](message string) error {
+	check := func() error {
+		return rt.New("not " + "found")
+	}
+
+	if message == "" {
+		return rt.New("not " + "found")
+	}
+
+	if err := check(); err != nil {
+		return err
+	}
+
+	return rt.NewSkipCaller(rt.SkipCall, message)
+}
+
+type message string
+
+func format(value string) string {
+	return value
+}
+
+func (s *Store) Put(key string) error {
+	if key == "" {
+		return rt.New(string(message("empty key")))
+	}
+
+	return rt.New(format("cannot put"))
+}
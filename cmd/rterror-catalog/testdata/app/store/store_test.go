@@ -0,0 +1,11 @@
+package store
+
+import (
+	"testing"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func TestStore(t *testing.T) {
+	_ = rterror.New("test error")
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSpec(test *testing.T, content string) string {
+	name := filepath.Join(test.TempDir(), "errors.yaml")
+
+	assert.NoError(test, os.WriteFile(name, []byte(content), 0o600))
+
+	return name
+}
+
+func TestGenerateExample(test *testing.T) {
+	example := filepath.Join("..", "..", "examples", "generated")
+
+	spec, err := loadSpec(filepath.Join(example, "errors.yaml"))
+
+	assert.NoError(test, err)
+
+	code, err := generate("errors.yaml", spec)
+
+	assert.NoError(test, err)
+
+	expected, err := os.ReadFile(filepath.Join(example, "errors_gen.go"))
+
+	assert.NoError(test, err)
+	assert.Equal(test, string(expected), string(code))
+
+	doc, err := generateDoc(spec)
+
+	assert.NoError(test, err)
+
+	expected, err = os.ReadFile(filepath.Join(example, "errors.md"))
+
+	assert.NoError(test, err)
+	assert.Equal(test, string(expected), string(doc))
+}
+
+func TestLoadSpecInvalid(test *testing.T) {
+	for spec, expected := range map[string]string{
+		"errors: []": "specification has no errors",
+		"errors: [{name: A, message: a, foo: 1}]":                                                          "field foo not found",
+		"errors: [{name: a, message: a}]":                                                                  `name "a" is not an exported Go identifier`,
+		"errors: [{name: A}]":                                                                              "A: missing message",
+		"errors: [{name: A, message: a}, {name: A, message: b}]":                                           `duplicated name "A"`,
+		"errors: [{name: A, message: '{id}'}]":                                                             "A: invalid message",
+		"errors: [{name: A, message: a, parameters: [{name: id, type: int}]}]":                             `parameter "id" is not used`,
+		"errors: [{name: A, message: '{id}', parameters: [{name: id, type: '[int'}]}]":                     `parameter "id" has invalid type`,
+		"errors: [{name: A, message: '{if}', parameters: [{name: if, type: int}]}]":                        `parameter name "if" is not a Go identifier`,
+		"errors: [{name: A, message: '{a}{a}', parameters: [{name: a, type: int}, {name: a, type: int}]}]": `duplicated parameter "a"`,
+		`{"errors": [{"name": "A", "message": "{id", "parameters": [{"name": "id", "type": "int"}]}]}`:     "A: invalid message",
+		"errors: [{name: A, message: '{rterror}', parameters: [{name: rterror, type: int}]}]":              `parameter name "rterror" is reserved`,
+		"errors: [{name: A, message: '{string}', parameters: [{name: string, type: int}]}]":                `parameter name "string" is reserved`,
+		"errors: [{name: A, message: '{error}', parameters: [{name: error, type: int}]}]":                  `parameter name "error" is reserved`,
+		"errors: [{name: A, message: '{true}', parameters: [{name: true, type: int}]}]":                    `parameter name "true" is reserved`,
+		"errors: [{name: A, message: '{map}', parameters: [{name: map, type: int}]}]":                      `parameter name "map" is not a Go identifier`,
+	} {
+		_, err := loadSpec(writeSpec(test, spec))
+
+		if assert.Error(test, err, spec) {
+			assert.Contains(test, err.Error(), expected, spec)
+		}
+	}
+}
+
+func TestRun(test *testing.T) {
+	name := writeSpec(test, `{"errors": [{"name": "A", "message": "a {x}", "parameters": [{"name": "x", "type": "int"}]}]}`)
+
+	test.Setenv("GOPACKAGE", "example")
+
+	assert.NoError(test, run(name, &options{}))
+
+	code, err := os.ReadFile(filepath.Join(filepath.Dir(name), "errors_gen.go"))
+
+	assert.NoError(test, err)
+	assert.Contains(test, string(code), "package example\n")
+	assert.Contains(test, string(code), "func NewA(x int) *rterror.RuntimeError {")
+
+	test.Setenv("GOPACKAGE", "")
+
+	assert.EqualError(test, run(name, &options{}), "missing package name")
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// data defines data used by templates.
+type data struct {
+	Source string
+	*Spec
+}
+
+var gCode = template.Must(template.New("code").Funcs(template.FuncMap{ // nolint: gochecknoglobals
+	"comment":    comment,
+	"parameters": parameters,
+	"quote":      strconv.Quote,
+}).Parse(`// Code generated by rterror-gen from {{.Source}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Imports}}
+	{{quote .}}
+{{- end}}
+{{if .Imports}}
+{{end}}	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// These constants define error codes stored in the rterror.FieldCode field.
+const (
+{{- range .Errors}}
+	Code{{.Name}} = {{quote .Code}}
+{{- end}}
+)
+{{range .Errors}}
+// New{{.Name}} creates a new {{.Name}} runtime error with location of the caller.
+{{- with .Description}}
+//
+{{comment .}}
+{{- end}}
+{{- with .DocsURL}}
+//
+// Documentation: {{.}}
+{{- end}}
+func New{{.Name}}({{parameters .Parameters}}) *rterror.RuntimeError {
+	return rterror.NewSkipCaller(rterror.SkipCall, {{quote .Message}}
+	{{- with .Parameters}}, map[string]interface{}{
+	{{- range .}}
+		{{quote .Name}}: {{.Name}},
+	{{- end}}
+	}{{end}})
+	{{- with .Kind}}.SetKind({{quote .}}){{end}}.SetFields(map[string]interface{}{
+		rterror.FieldCode: Code{{.Name}},
+	{{- with .HTTPStatus}}
+		rterror.FieldHTTPStatus: {{.}},
+	{{- end}}
+	{{- if .Retryable}}
+		rterror.FieldRetryable: true,
+	{{- end}}
+	{{- with .DocsURL}}
+		rterror.FieldDocsURL: {{quote .}},
+	{{- end}}
+	})
+}
+
+// Is{{.Name}} returns true if error chain contains the {{.Name}} runtime error.
+func Is{{.Name}}(err error) bool {
+	for _, r := range rterror.RuntimeErrors(err) {
+		if r.GetField(rterror.FieldCode) == Code{{.Name}} {
+			return true
+		}
+	}
+
+	return false
+}
+{{end -}}
+`))
+
+var gDoc = template.Must(template.New("doc").Funcs(template.FuncMap{ // nolint: gochecknoglobals
+	"cell": cell,
+}).Parse(`# Errors
+
+| Code | Kind | HTTP status | Retryable | Message | Description |
+|------|------|-------------|-----------|---------|-------------|
+{{- range .Errors}}
+| {{if .DocsURL}}[{{.Code}}]({{.DocsURL}}){{else}}{{.Code}}{{end}} | {{with .Kind}}` + "`{{.}}`" + `{{end}} | {{with .HTTPStatus}}{{.}}{{end}} | {{if .Retryable}}yes{{else}}no{{end}} | ` + "`{{cell .Message}}`" + ` | {{cell .Description}} |
+{{- end}}
+`))
+
+// generate generates Go source code with error constructors from specification.
+func generate(source string, spec *Spec) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if err := gCode.Execute(&buffer, &data{Source: source, Spec: spec}); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buffer.Bytes())
+}
+
+// generateDoc generates Markdown documentation of errors from specification.
+func generateDoc(spec *Spec) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if err := gDoc.Execute(&buffer, spec); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+
+	for index, line := range lines {
+		lines[index] = strings.TrimRight("// "+line, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func parameters(list []*Parameter) string {
+	p := make([]string, 0, len(list))
+
+	for _, parameter := range list {
+		p = append(p, parameter.Name+" "+parameter.Type)
+	}
+
+	return strings.Join(p, ", ")
+}
+
+func cell(value string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(strings.TrimSpace(value))
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command rterror-gen generates typed error constructors from declarative
+// specification of domain errors written in YAML or in JSON. For each error
+// it generates the New<Name>() constructor with typed parameters, that uses
+// the NewSkipCaller() function to report location of the caller, and the
+// Is<Name>() function. Optionally, it generates Markdown documentation.
+// Example of specification:
+//
+//  package: users
+//  errors:
+//    - name: UserNotFound
+//      kind: not_found
+//      message: "user {id} not found"
+//      parameters:
+//        - name: id
+//          type: int64
+//      http_status: 404
+//      retryable: false
+//      docs_url: https://example.com/errors/user-not-found
+//      description: User with provided identifier does not exist.
+//
+// Usage with the go generate command:
+//
+//  //go:generate go run gitlab.com/tymonx/go-error/cmd/rterror-gen -doc errors.md errors.yaml
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+type options struct {
+	output      string
+	doc         string
+	packageName string
+}
+
+func main() {
+	var o options
+
+	flag.StringVar(&o.output, "o", "", "output Go file, default is specification file name with the _gen.go suffix")
+	flag.StringVar(&o.doc, "doc", "", "output Markdown documentation file")
+	flag.StringVar(&o.packageName, "package", "", "package name, default is from specification or $GOPACKAGE")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: rterror-gen [flags] spec.yaml\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	rterror.Main(func() error {
+		return run(flag.Arg(0), &o)
+	})
+}
+
+func run(name string, o *options) error {
+	spec, err := loadSpec(name)
+
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case o.packageName != "":
+		spec.Package = o.packageName
+	case spec.Package == "":
+		spec.Package = os.Getenv("GOPACKAGE")
+	}
+
+	if spec.Package == "" {
+		return errors.New("missing package name")
+	}
+
+	if o.output == "" {
+		o.output = strings.TrimSuffix(name, filepath.Ext(name)) + "_gen.go"
+	}
+
+	code, err := generate(filepath.Base(name), spec)
+
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(o.output, code, 0o644); err != nil { // nolint: gosec
+		return err
+	}
+
+	if o.doc == "" {
+		return nil
+	}
+
+	doc, err := generateDoc(spec)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(o.doc, doc, 0o644) // nolint: gosec
+}
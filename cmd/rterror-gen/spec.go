@@ -0,0 +1,211 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"text/template/parse"
+
+	"gitlab.com/tymonx/go-formatter/formatter"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec defines declarative specification of domain errors. It can be written
+// in YAML or in JSON.
+type Spec struct {
+	Package string       `yaml:"package"`
+	Imports []string     `yaml:"imports"`
+	Errors  []*ErrorSpec `yaml:"errors"`
+}
+
+// ErrorSpec defines specification of single domain error. Message is
+// a template with named placeholders {name} that refer to parameters.
+type ErrorSpec struct {
+	Name        string       `yaml:"name"`
+	Code        string       `yaml:"code"`
+	Kind        string       `yaml:"kind"`
+	Message     string       `yaml:"message"`
+	Parameters  []*Parameter `yaml:"parameters"`
+	HTTPStatus  int          `yaml:"http_status"`
+	Retryable   bool         `yaml:"retryable"`
+	DocsURL     string       `yaml:"docs_url"`
+	Description string       `yaml:"description"`
+}
+
+// Parameter defines typed parameter of error constructor.
+type Parameter struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// loadSpec loads specification from YAML or JSON file. Unknown keys are reported as error.
+func loadSpec(name string) (*Spec, error) {
+	data, err := os.ReadFile(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return &spec, nil
+}
+
+func (s *Spec) validate() error {
+	if len(s.Errors) == 0 {
+		return errors.New("specification has no errors")
+	}
+
+	names := make(map[string]bool)
+
+	for index, e := range s.Errors {
+		if err := e.validate(); err != nil {
+			return fmt.Errorf("error %d: %w", index, err)
+		}
+
+		if names[e.Name] {
+			return fmt.Errorf("error %d: duplicated name %q", index, e.Name)
+		}
+
+		names[e.Name] = true
+	}
+
+	return nil
+}
+
+func (e *ErrorSpec) validate() error {
+	if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+		return fmt.Errorf("name %q is not an exported Go identifier", e.Name)
+	}
+
+	if e.Code == "" {
+		e.Code = e.Name
+	}
+
+	if e.Message == "" {
+		return fmt.Errorf("%s: missing message", e.Name)
+	}
+
+	arguments := make(map[string]interface{}, len(e.Parameters))
+
+	for _, p := range e.Parameters {
+		if !token.IsIdentifier(p.Name) {
+			return fmt.Errorf("%s: parameter name %q is not a Go identifier", e.Name, p.Name)
+		}
+
+		// Parameters must not shadow the rterror package or predeclared
+		// identifiers like string or true used by generated function body
+		if (p.Name == "rterror") || (types.Universe.Lookup(p.Name) != nil) {
+			return fmt.Errorf("%s: parameter name %q is reserved", e.Name, p.Name)
+		}
+
+		if _, ok := arguments[p.Name]; ok {
+			return fmt.Errorf("%s: duplicated parameter %q", e.Name, p.Name)
+		}
+
+		if _, err := parser.ParseExpr(p.Type); err != nil {
+			return fmt.Errorf("%s: parameter %q has invalid type %q", e.Name, p.Name, p.Type)
+		}
+
+		arguments[p.Name] = nil
+	}
+
+	return e.validateMessage(arguments)
+}
+
+// validateMessage formats message to check that it is valid and it checks
+// that all placeholders refer to parameters and all parameters are used.
+func (e *ErrorSpec) validateMessage(arguments map[string]interface{}) error {
+	if _, err := formatter.New().Format(e.Message, arguments); err != nil {
+		return fmt.Errorf("%s: invalid message: %w", e.Name, err)
+	}
+
+	tree := parse.New("message")
+	tree.Mode = parse.SkipFuncCheck
+
+	if _, err := tree.Parse(e.Message, formatter.DefaultLeftDelimiter, formatter.DefaultRightDelimiter,
+		make(map[string]*parse.Tree)); err != nil {
+		return fmt.Errorf("%s: invalid message: %w", e.Name, err)
+	}
+
+	used := make(map[string]bool)
+
+	identifiers(tree.Root, used)
+
+	for _, p := range e.Parameters {
+		if !used[p.Name] {
+			return fmt.Errorf("%s: parameter %q is not used in message", e.Name, p.Name)
+		}
+	}
+
+	return nil
+}
+
+// identifiers collects all identifiers from template node.
+func identifiers(node parse.Node, used map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, child := range n.Nodes {
+				identifiers(child, used)
+			}
+		}
+	case *parse.ActionNode:
+		identifiers(n.Pipe, used)
+	case *parse.PipeNode:
+		if n != nil {
+			for _, command := range n.Cmds {
+				identifiers(command, used)
+			}
+		}
+	case *parse.CommandNode:
+		for _, argument := range n.Args {
+			identifiers(argument, used)
+		}
+	case *parse.ChainNode:
+		identifiers(n.Node, used)
+	case *parse.IdentifierNode:
+		used[n.Ident] = true
+	case *parse.IfNode:
+		identifiers(n.Pipe, used)
+		identifiers(n.List, used)
+		identifiers(n.ElseList, used)
+	case *parse.RangeNode:
+		identifiers(n.Pipe, used)
+		identifiers(n.List, used)
+		identifiers(n.ElseList, used)
+	case *parse.WithNode:
+		identifiers(n.Pipe, used)
+		identifiers(n.List, used)
+		identifiers(n.ElseList, used)
+	}
+}
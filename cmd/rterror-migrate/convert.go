@@ -0,0 +1,109 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// convert converts printf format to message with positional placeholders {pN}.
+// Only the %v verb is converted to positional placeholder {pN}, other verbs
+// like %s or %d render some values differently, for example []byte or
+// time.Duration. They are converted to the printf template function with
+// positional placeholder. Braces are escaped. The %w verb must be the last
+// verb at the end of format, its argument is wrapped with the Wrap() method
+// and it is removed from message together with the preceding separator. It
+// returns number of arguments used by format and position of wrapped argument
+// or -1.
+func convert(format string, verbs, wrap bool) (message string, arguments, wrapped int, err error) {
+	var builder strings.Builder
+
+	wrapped = -1
+
+	for index := 0; index < len(format); index++ {
+		c := format[index]
+
+		switch {
+		case c == '{':
+			builder.WriteString(`{"{"}`)
+			continue
+		case c == '}':
+			builder.WriteString(`{"}"}`)
+			continue
+		case (c != '%') || !verbs:
+			builder.WriteByte(c)
+			continue
+		}
+
+		verb, ok := parseVerb(format[index+1:])
+
+		index += len(verb) - 1
+
+		switch {
+		case !ok:
+			return "", 0, 0, errors.New("incomplete verb " + verb)
+		case verb == "%%":
+			builder.WriteByte('%')
+		case verb == "%v":
+			builder.WriteString("{p" + strconv.Itoa(arguments) + "}")
+			arguments++
+		case (verb == "%w") && wrap && (index+1 == len(format)):
+			wrapped = arguments
+			arguments++
+		case verb == "%w":
+			return "", 0, 0, errors.New("the %w verb is supported only at end of format")
+		case strings.ContainsAny(verb, "[*"):
+			return "", 0, 0, errors.New("unsupported verb " + verb)
+		default:
+			builder.WriteString("{printf " + strconv.Quote(verb) + " p" + strconv.Itoa(arguments) + "}")
+			arguments++
+		}
+	}
+
+	message = builder.String()
+
+	if wrapped >= 0 {
+		message = strings.TrimSuffix(strings.TrimRight(message, " "), ":")
+
+		if message == "" {
+			return "", 0, 0, errors.New("message is empty without the %w verb")
+		}
+	}
+
+	return message, arguments, wrapped, nil
+}
+
+// parseVerb returns printf verb with flags, width, precision and verb character
+// from the beginning of format without leading %. It returns false if verb is incomplete.
+func parseVerb(format string) (verb string, ok bool) {
+	for length := 0; length < len(format); length++ {
+		if c := format[length]; ((c >= 'a') && (c <= 'z')) || ((c >= 'A') && (c <= 'Z')) || (c == '%') {
+			return "%" + format[:length+1], true
+		}
+	}
+
+	return "%" + format, false
+}
+
+// quote quotes message using the same kind of string literal as original string literal.
+func quote(original, message string) string {
+	if strings.HasPrefix(original, "`") && !strings.Contains(message, "`") && strconv.CanBackquote(message) {
+		return "`" + message + "`"
+	}
+
+	return strconv.Quote(message)
+}
@@ -0,0 +1,218 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// contextLines defines number of unchanged lines around changes in unified diff.
+const contextLines = 3
+
+type operation struct {
+	kind byte // One of ' ', '-' or '+'
+	line string
+}
+
+// diff returns unified diff between old and new text. It returns empty string if texts are equal.
+func diff(oldName, newName string, oldText, newText []byte) string {
+	operations := compare(splitLines(string(oldText)), splitLines(string(newText)))
+
+	var builder strings.Builder
+
+	oldLine, newLine := 0, 0
+	index := 0
+
+	for index < len(operations) {
+		if operations[index].kind == ' ' {
+			oldLine++
+			newLine++
+			index++
+
+			continue
+		}
+
+		if builder.Len() == 0 {
+			builder.WriteString("--- " + oldName + "\n+++ " + newName + "\n")
+		}
+
+		start := index - contextLines
+
+		if start < 0 {
+			start = 0
+		}
+
+		oldLine -= index - start
+		newLine -= index - start
+		end := hunkEnd(operations, index)
+
+		oldLine, newLine = writeHunk(&builder, operations[start:end], oldLine, newLine)
+		index = end
+	}
+
+	return builder.String()
+}
+
+// hunkEnd returns end of hunk that starts with change at index. Changes
+// separated by less than two times context lines are merged into single hunk.
+func hunkEnd(operations []operation, index int) int {
+	for index < len(operations) {
+		if operations[index].kind != ' ' {
+			index++
+			continue
+		}
+
+		next := index
+
+		for (next < len(operations)) && (operations[next].kind == ' ') {
+			next++
+		}
+
+		if (next == len(operations)) || (next-index > 2*contextLines) {
+			if index += contextLines; index > len(operations) {
+				index = len(operations)
+			}
+
+			return index
+		}
+
+		index = next
+	}
+
+	return index
+}
+
+func writeHunk(builder *strings.Builder, operations []operation, oldLine, newLine int) (oldEnd, newEnd int) {
+	oldCount, newCount := 0, 0
+
+	for _, o := range operations {
+		if o.kind != '+' {
+			oldCount++
+		}
+
+		if o.kind != '-' {
+			newCount++
+		}
+	}
+
+	builder.WriteString("@@ -" + hunkRange(oldLine, oldCount) + " +" + hunkRange(newLine, newCount) + " @@\n")
+
+	for _, o := range operations {
+		builder.WriteByte(o.kind)
+		builder.WriteString(o.line)
+
+		if !strings.HasSuffix(o.line, "\n") {
+			builder.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+
+	return oldLine + oldCount, newLine + newCount
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return strconv.Itoa(line) + ",0"
+	}
+
+	return strconv.Itoa(line+1) + "," + strconv.Itoa(count)
+}
+
+// compare returns the shortest edit script between old and new lines using
+// the Myers' difference algorithm.
+func compare(oldLines, newLines []string) []operation {
+	n, m := len(oldLines), len(newLines)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+
+	var trace [][]int
+
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v...))
+
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+
+			if (k == -d) || ((k != d) && (v[offset+k-1] < v[offset+k+1])) {
+				x = v[offset+k+1]
+			}
+
+			y := x - k
+
+			for (x < n) && (y < m) && (oldLines[x] == newLines[y]) {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if (x >= n) && (y >= m) {
+				return backtrack(trace, offset, oldLines, newLines)
+			}
+		}
+	}
+
+	return nil
+}
+
+func backtrack(trace [][]int, offset int, oldLines, newLines []string) []operation {
+	x, y := len(oldLines), len(newLines)
+	operations := make([]operation, 0, x+y)
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		previous := k - 1
+
+		if (k == -d) || ((k != d) && (v[offset+k-1] < v[offset+k+1])) {
+			previous = k + 1
+		}
+
+		previousX := v[offset+previous]
+		previousY := previousX - previous
+
+		for (x > previousX) && (y > previousY) {
+			x--
+			y--
+			operations = append(operations, operation{kind: ' ', line: oldLines[x]})
+		}
+
+		if d > 0 {
+			if x == previousX {
+				operations = append(operations, operation{kind: '+', line: newLines[previousY]})
+			} else {
+				operations = append(operations, operation{kind: '-', line: oldLines[previousX]})
+			}
+		}
+
+		x, y = previousX, previousY
+	}
+
+	for i, j := 0, len(operations)-1; i < j; i, j = i+1, j-1 {
+		operations[i], operations[j] = operations[j], operations[i]
+	}
+
+	return operations
+}
+
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(test *testing.T) {
+	lines := strings.Split("a b c d e f g h i j k l m n o p", " ")
+	oldText := strings.Join(lines, "\n") + "\n"
+
+	lines[1] = "B"
+	lines[14] = "O"
+	newText := strings.Join(append(lines[:12], append([]string{"x"}, lines[12:]...)...), "\n") + "\n"
+
+	assert.Equal(test, `--- a.orig
++++ a
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,7 +10,8 @@
+ j
+ k
+ l
++x
+ m
+ n
+-o
++O
+ p
+`, diff("a.orig", "a", []byte(oldText), []byte(newText)))
+
+	assert.Empty(test, diff("a.orig", "a", []byte(oldText), []byte(oldText)))
+	assert.Equal(test, "--- a.orig\n+++ a\n@@ -0,0 +1,1 @@\n+a\n", diff("a.orig", "a", nil, []byte("a\n")))
+	assert.Equal(test, "--- a.orig\n+++ a\n@@ -1,1 +1,1 @@\n-a\n\\ No newline at end of file\n+b\n",
+		diff("a.orig", "a", []byte("a"), []byte("b\n")))
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command rterror-migrate rewrites errors.New() and fmt.Errorf() calls to
+// rterror.New() calls. The %v verb is converted to positional placeholder {pN},
+// other verbs are converted to the printf template function like
+// {printf "%s" pN} and the %w verb at the end of format is converted to the
+// Wrap() method. Optionally, it also rewrites the New(), Errorf(), Wrap() and
+// Wrapf() calls from the github.com/pkg/errors package. Wrap() from the
+// github.com/pkg/errors package returns nil for nil error and migrated call
+// does not, these calls are rewritten only inside the if err != nil statement
+// or when wrapped error is created in place. Otherwise, a warning is printed.
+// Package level calls like sentinel errors are not rewritten.
+//
+// By default, it prints unified diff of changes without modifying files:
+//
+//  rterror-migrate ./...
+//
+// Use the -w flag to write changes to source files:
+//
+//  rterror-migrate -w -pkg-errors ./pkg/...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type options struct {
+	write     bool
+	pkgErrors bool
+}
+
+func main() {
+	var o options
+
+	flag.BoolVar(&o.write, "w", false, "write result to source files instead of printing diff")
+	flag.BoolVar(&o.pkgErrors, "pkg-errors", false, "also rewrite github.com/pkg/errors calls")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: rterror-migrate [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	paths := flag.Args()
+
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	status := 0
+
+	for _, path := range paths {
+		if err := walk(path, &o); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			status = 1
+		}
+	}
+
+	os.Exit(status)
+}
+
+// walk migrates Go source file or all Go source files in directory. The ./...
+// pattern suffix is accepted. Directories vendor, testdata and hidden ones are skipped.
+func walk(path string, o *options) error {
+	path = strings.TrimSuffix(path, "...")
+
+	if path == "" {
+		path = "."
+	}
+
+	return filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		base := entry.Name()
+
+		if entry.IsDir() {
+			if (name != path) && ((base == "vendor") || (base == "testdata") ||
+				strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(base, ".go") {
+			return nil
+		}
+
+		return process(name, o)
+	})
+}
+
+func process(name string, o *options) error {
+	src, err := os.ReadFile(name)
+
+	if err != nil {
+		return err
+	}
+
+	result, warnings, err := migrate(name, src, o.pkgErrors)
+
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	if bytes.Equal(src, result) {
+		return nil
+	}
+
+	if o.write {
+		info, err := os.Stat(name)
+
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(name, result, info.Mode().Perm())
+	}
+
+	_, err = fmt.Print(diff(name+".orig", name, src, result))
+
+	return err
+}
@@ -0,0 +1,565 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// These constants define import paths of migrated packages.
+const (
+	rterrorPath   = "gitlab.com/tymonx/go-error/rterror"
+	errorsPath    = "errors"
+	fmtPath       = "fmt"
+	pkgErrorsPath = "github.com/pkg/errors"
+)
+
+// maxPasses defines maximum number of migration passes. Nested calls are
+// migrated in the next pass after the enclosing call was migrated.
+const maxPasses = 16
+
+type edit struct {
+	start int
+	end   int
+	text  string
+}
+
+// rewrite defines how error creating call is migrated.
+type rewrite struct {
+	message int  // Position of message argument
+	wrapped int  // Position of wrapped error argument or -1
+	verbs   bool // Message contains printf format verbs
+	wrap    bool // Message can contain the %w verb
+	guard   bool // Wrapped error must be checked against nil
+}
+
+type migrator struct {
+	fset      *token.FileSet
+	file      *ast.File
+	src       []byte
+	name      string
+	pkgErrors bool
+	imports   map[string]string
+	uses      map[string]int
+	rewritten map[string]int
+	edits     []edit
+	ranges    [][2]token.Pos
+	imported  bool
+	removed   map[*ast.ImportSpec]bool
+	pending   bool
+	warnings  []string
+	stack     []ast.Node
+}
+
+// migrate rewrites errors.New(), fmt.Errorf() and optionally github.com/pkg/errors
+// calls in functions to rterror calls. Package level calls like sentinel errors
+// are not migrated. It returns migrated source and warnings about calls that
+// cannot be migrated.
+func migrate(filename string, src []byte, pkgErrors bool) (result []byte, warnings []string, err error) {
+	result = src
+
+	for pass := 0; pass < maxPasses; pass++ {
+		m, err := newMigrator(filename, result, pkgErrors)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m.run()
+
+		if pass == 0 {
+			warnings = m.warnings
+		}
+
+		if len(m.edits) == 0 {
+			break
+		}
+
+		if result, err = m.apply(); err != nil {
+			return nil, nil, err
+		}
+
+		if !m.pending {
+			break
+		}
+	}
+
+	return result, warnings, nil
+}
+
+func newMigrator(filename string, src []byte, pkgErrors bool) (*migrator, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m := &migrator{
+		fset:      fset,
+		file:      file,
+		src:       src,
+		name:      "rterror",
+		pkgErrors: pkgErrors,
+		imports:   make(map[string]string),
+		uses:      make(map[string]int),
+		rewritten: make(map[string]int),
+		removed:   make(map[*ast.ImportSpec]bool),
+	}
+
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		if path == rterrorPath {
+			m.name = name
+			m.imported = true
+		}
+
+		m.imports[name] = path
+	}
+
+	return m, nil
+}
+
+func (m *migrator) run() {
+	ast.Inspect(m.file, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok {
+			if ident, ok := selector.X.(*ast.Ident); ok && (ident.Obj == nil) {
+				m.uses[ident.Name]++
+			}
+		}
+
+		return true
+	})
+
+	for _, decl := range m.file.Decls {
+		if function, ok := decl.(*ast.FuncDecl); ok && (function.Body != nil) {
+			ast.Inspect(function.Body, func(node ast.Node) bool {
+				if node == nil {
+					m.stack = m.stack[:len(m.stack)-1]
+					return true
+				}
+
+				m.stack = append(m.stack, node)
+
+				if call, ok := node.(*ast.CallExpr); ok {
+					m.call(call)
+				}
+
+				return true
+			})
+		}
+	}
+}
+
+func (m *migrator) call(call *ast.CallExpr) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+
+	if !ok {
+		return
+	}
+
+	ident, ok := selector.X.(*ast.Ident)
+
+	if !ok || (ident.Obj != nil) {
+		return
+	}
+
+	r, ok := m.lookup(m.imports[ident.Name], selector.Sel.Name)
+
+	if !ok {
+		return
+	}
+
+	name := ident.Name + "." + selector.Sel.Name
+
+	if reason := m.rewrite(call, r); reason != "" {
+		m.warnings = append(m.warnings, m.fset.Position(call.Pos()).String()+": cannot migrate "+name+": "+reason)
+		return
+	}
+
+	if m.nested(call) {
+		m.pending = true
+		return
+	}
+
+	m.rewritten[ident.Name]++
+	m.ranges = append(m.ranges, [2]token.Pos{call.Pos(), call.End()})
+}
+
+func (m *migrator) lookup(path, function string) (rewrite, bool) {
+	switch {
+	case (path == errorsPath) && (function == "New"):
+		return rewrite{message: 0, wrapped: -1}, true
+	case (path == fmtPath) && (function == "Errorf"):
+		return rewrite{message: 0, wrapped: -1, verbs: true, wrap: true}, true
+	case (path != pkgErrorsPath) || !m.pkgErrors:
+		return rewrite{}, false
+	case function == "New":
+		return rewrite{message: 0, wrapped: -1}, true
+	case function == "Errorf":
+		return rewrite{message: 0, wrapped: -1, verbs: true}, true
+	case function == "Wrap":
+		return rewrite{message: 1, wrapped: 0, guard: true}, true
+	case function == "Wrapf":
+		return rewrite{message: 1, wrapped: 0, verbs: true, guard: true}, true
+	default:
+		return rewrite{}, false
+	}
+}
+
+// rewrite adds edits that migrate call. Edits are not added if call is nested
+// in already migrated call. It returns reason why call cannot be migrated.
+func (m *migrator) rewrite(call *ast.CallExpr, r rewrite) string {
+	if call.Ellipsis.IsValid() {
+		return "variadic arguments"
+	}
+
+	if len(call.Args) <= r.message {
+		return "missing message argument"
+	}
+
+	literal, ok := call.Args[r.message].(*ast.BasicLit)
+
+	if !ok || (literal.Kind != token.STRING) {
+		return "message is not a string literal"
+	}
+
+	format, err := strconv.Unquote(literal.Value)
+
+	if err != nil {
+		return err.Error()
+	}
+
+	message, arguments, wrapped, err := convert(format, r.verbs, r.wrap)
+
+	if err != nil {
+		return err.Error()
+	}
+
+	if count := len(call.Args) - r.message - 1; count != arguments {
+		return "message uses " + strconv.Itoa(arguments) + " arguments, got " + strconv.Itoa(count)
+	}
+
+	if wrapped >= 0 {
+		r.wrapped = r.message + 1 + wrapped
+	}
+
+	if r.guard && !m.guarded(call.Args[r.wrapped]) {
+		return "wrapped error may be nil, call is not guarded with if " +
+			m.text(call.Args[r.wrapped].Pos(), call.Args[r.wrapped].End()) + " != nil"
+	}
+
+	if m.nested(call) {
+		return ""
+	}
+
+	m.replace(call.Fun.Pos(), call.Fun.End(), m.name+".New")
+
+	if (r.wrapped >= 0) && (r.wrapped < r.message) {
+		m.replace(call.Args[r.wrapped].Pos(), call.Args[r.message].Pos(), "")
+	}
+
+	if message != format {
+		m.replace(literal.Pos(), literal.End(), quote(literal.Value, message))
+	}
+
+	if r.wrapped > r.message {
+		m.replace(call.Args[r.wrapped].Pos(), call.Rparen, "")
+	}
+
+	if r.wrapped >= 0 {
+		m.replace(call.End(), call.End(), ".Wrap("+m.text(call.Args[r.wrapped].Pos(), call.Args[r.wrapped].End())+")")
+	}
+
+	return ""
+}
+
+// guarded returns true if wrapped error is never nil. It is a call that creates
+// a new error or an expression checked against nil by the condition of enclosing
+// if statement in the same function. Wrap() from the github.com/pkg/errors package
+// returns nil for nil error and the migrated call does not.
+func (m *migrator) guarded(wrapped ast.Expr) bool {
+	if m.creates(wrapped) {
+		return true
+	}
+
+	name := m.text(wrapped.Pos(), wrapped.End())
+
+	for index := len(m.stack) - 1; index > 0; index-- {
+		switch node := m.stack[index-1].(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			if (node.Body == m.stack[index]) && m.notNil(node.Cond, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// creates returns true if expression is a call that always creates a new error.
+func (m *migrator) creates(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+
+	if !ok {
+		return false
+	}
+
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+
+	if !ok {
+		return false
+	}
+
+	ident, ok := selector.X.(*ast.Ident)
+
+	if !ok || (ident.Obj != nil) {
+		return false
+	}
+
+	path, function := m.imports[ident.Name], selector.Sel.Name
+
+	if _, ok := m.lookup(path, function); ok || (path == rterrorPath) {
+		return (function == "New") || (function == "Errorf")
+	}
+
+	return false
+}
+
+// notNil returns true if condition requires that named expression is not nil.
+func (m *migrator) notNil(cond ast.Expr, name string) bool {
+	switch expr := cond.(type) {
+	case *ast.ParenExpr:
+		return m.notNil(expr.X, name)
+	case *ast.BinaryExpr:
+		switch expr.Op {
+		case token.LAND:
+			return m.notNil(expr.X, name) || m.notNil(expr.Y, name)
+		case token.NEQ:
+			return (isNil(expr.Y) && (m.text(expr.X.Pos(), expr.X.End()) == name)) ||
+				(isNil(expr.X) && (m.text(expr.Y.Pos(), expr.Y.End()) == name))
+		}
+	}
+
+	return false
+}
+
+func isNil(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+
+	return ok && (ident.Name == "nil")
+}
+
+func (m *migrator) nested(call *ast.CallExpr) bool {
+	for _, r := range m.ranges {
+		if (r[0] <= call.Pos()) && (call.End() <= r[1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *migrator) replace(start, end token.Pos, text string) {
+	m.edits = append(m.edits, edit{
+		start: m.offset(start),
+		end:   m.offset(end),
+		text:  text,
+	})
+}
+
+func (m *migrator) offset(pos token.Pos) int {
+	return m.fset.Position(pos).Offset
+}
+
+func (m *migrator) text(start, end token.Pos) string {
+	return string(m.src[m.offset(start):m.offset(end)])
+}
+
+// apply applies edits, updates imports and formats migrated source.
+func (m *migrator) apply() ([]byte, error) {
+	names := make([]string, 0, len(m.rewritten))
+
+	for name := range m.rewritten {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if m.uses[name] == m.rewritten[name] {
+			m.removeImport(m.imports[name], name)
+		}
+	}
+
+	m.addImport()
+
+	sort.SliceStable(m.edits, func(i, j int) bool {
+		return m.edits[i].start < m.edits[j].start
+	})
+
+	var builder strings.Builder
+
+	last := 0
+
+	for _, e := range m.edits {
+		builder.Write(m.src[last:e.start])
+		builder.WriteString(e.text)
+		last = e.end
+	}
+
+	builder.Write(m.src[last:])
+
+	return format.Source([]byte(builder.String()))
+}
+
+func (m *migrator) removeImport(path, name string) {
+	for _, decl := range m.file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+
+		if !ok || (gen.Tok != token.IMPORT) {
+			continue
+		}
+
+		removed := 0
+
+		for _, spec := range gen.Specs {
+			if m.matchImport(spec.(*ast.ImportSpec), path, name) {
+				removed++
+			}
+		}
+
+		switch {
+		case removed == 0:
+		case (removed == len(gen.Specs)) && (m.imported || !m.firstImport(gen)):
+			m.removeLines(gen.Pos(), gen.End())
+		case !gen.Lparen.IsValid():
+			m.replace(gen.Pos(), gen.End(), "import "+strconv.Quote(rterrorPath))
+			m.imported = true
+		default:
+			for _, spec := range gen.Specs {
+				if s := spec.(*ast.ImportSpec); m.matchImport(s, path, name) {
+					m.removeLines(importStart(s), importEnd(s))
+					m.removed[s] = true
+				}
+			}
+		}
+	}
+}
+
+func (m *migrator) addImport() {
+	if m.imported {
+		return
+	}
+
+	for _, decl := range m.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && (gen.Tok == token.IMPORT) {
+			if gen.Lparen.IsValid() {
+				line := m.fset.File(gen.Rparen).LineStart(m.fset.Position(gen.Rparen).Line)
+				m.replace(line, line, m.separator(gen)+"\t"+strconv.Quote(rterrorPath)+"\n")
+			} else {
+				m.replace(gen.Pos(), gen.End(), "import (\n\t"+m.text(gen.Specs[0].Pos(), gen.Specs[0].End())+
+					"\n"+m.separator(gen)+"\t"+strconv.Quote(rterrorPath)+"\n)")
+			}
+
+			return
+		}
+	}
+
+	m.replace(m.file.Name.End(), m.file.Name.End(), "\n\nimport "+strconv.Quote(rterrorPath))
+}
+
+// separator returns empty line if the last kept import from declaration is
+// from the standard library. It separates the rterror import from standard library imports.
+func (m *migrator) separator(gen *ast.GenDecl) string {
+	for index := len(gen.Specs) - 1; index >= 0; index-- {
+		spec := gen.Specs[index].(*ast.ImportSpec)
+
+		if m.removed[spec] {
+			continue
+		}
+
+		if path, _ := strconv.Unquote(spec.Path.Value); strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			return ""
+		}
+
+		return "\n"
+	}
+
+	return ""
+}
+
+func (m *migrator) matchImport(spec *ast.ImportSpec, path, name string) bool {
+	if p, _ := strconv.Unquote(spec.Path.Value); p != path {
+		return false
+	}
+
+	return (spec.Name == nil) || (spec.Name.Name == name)
+}
+
+func (m *migrator) firstImport(gen *ast.GenDecl) bool {
+	for _, decl := range m.file.Decls {
+		if g, ok := decl.(*ast.GenDecl); ok && (g.Tok == token.IMPORT) {
+			return g == gen
+		}
+	}
+
+	return false
+}
+
+// removeLines removes all lines between start and end positions.
+func (m *migrator) removeLines(start, end token.Pos) {
+	file := m.fset.File(start)
+	first := file.LineStart(file.Line(start))
+	last := file.Line(end) + 1
+
+	if last > file.LineCount() {
+		m.replace(first, end, "")
+		return
+	}
+
+	m.replace(first, file.LineStart(last), "")
+}
+
+func importStart(spec *ast.ImportSpec) token.Pos {
+	if spec.Doc != nil {
+		return spec.Doc.Pos()
+	}
+
+	return spec.Pos()
+}
+
+func importEnd(spec *ast.ImportSpec) token.Pos {
+	if spec.Comment != nil {
+		return spec.Comment.End()
+	}
+
+	return spec.End()
+}
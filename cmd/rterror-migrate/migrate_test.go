@@ -0,0 +1,144 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var gUpdate = flag.Bool("update", false, "update golden files") // nolint: gochecknoglobals
+
+func TestMigrate(test *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input"))
+
+	assert.NoError(test, err)
+	assert.NotEmpty(test, inputs)
+
+	for _, input := range inputs {
+		src, err := os.ReadFile(input)
+
+		assert.NoError(test, err)
+
+		result, warnings, err := migrate(input, src, strings.HasPrefix(filepath.Base(input), "pkgerrors"))
+
+		assert.NoError(test, err, input)
+		assert.Empty(test, warnings, input)
+
+		golden := strings.TrimSuffix(input, ".input") + ".golden"
+
+		if *gUpdate {
+			assert.NoError(test, os.WriteFile(golden, result, 0o600))
+		}
+
+		expected, err := os.ReadFile(golden)
+
+		assert.NoError(test, err)
+		assert.Equal(test, string(expected), string(result), input)
+	}
+}
+
+func TestMigrateWarnings(test *testing.T) {
+	src := []byte(`package warnings
+
+import "fmt"
+
+func Fail(name string, arguments ...interface{}) error {
+	if name == "" {
+		return fmt.Errorf("invalid %[1]x", name)
+	}
+
+	return fmt.Errorf(name, arguments...)
+}
+`)
+
+	result, warnings, err := migrate("warnings.go", src, false)
+
+	assert.NoError(test, err)
+	assert.Equal(test, string(src), string(result))
+	assert.Equal(test, []string{
+		"warnings.go:7:10: cannot migrate fmt.Errorf: unsupported verb %[1]x",
+		"warnings.go:10:9: cannot migrate fmt.Errorf: variadic arguments",
+	}, warnings)
+}
+
+func TestMigrateUnguardedWrap(test *testing.T) {
+	src := []byte(`package warnings
+
+import "github.com/pkg/errors"
+
+func Fail(err error, other error) error {
+	if other != nil {
+		return errors.Wrap(err, "failed")
+	}
+
+	if err != nil {
+		return func() error {
+			return errors.Wrapf(err, "failed %v", other)
+		}()
+	}
+
+	return errors.Wrap(err, "failed")
+}
+`)
+
+	result, warnings, err := migrate("warnings.go", src, true)
+
+	assert.NoError(test, err)
+	assert.Equal(test, string(src), string(result))
+	assert.Equal(test, []string{
+		"warnings.go:7:10: cannot migrate errors.Wrap: wrapped error may be nil, call is not guarded with if err != nil",
+		"warnings.go:12:11: cannot migrate errors.Wrapf: wrapped error may be nil, call is not guarded with if err != nil",
+		"warnings.go:16:9: cannot migrate errors.Wrap: wrapped error may be nil, call is not guarded with if err != nil",
+	}, warnings)
+}
+
+func TestConvert(test *testing.T) {
+	message, arguments, wrapped, err := convert("a {%s} %d%%: %w", true, true)
+
+	assert.NoError(test, err)
+	assert.Equal(test, `a {"{"}{printf "%s" p0}{"}"} {printf "%d" p1}%`, message)
+	assert.Equal(test, 3, arguments)
+	assert.Equal(test, 2, wrapped)
+
+	_, _, _, err = convert("a %w %s", true, true)
+
+	assert.Error(test, err)
+
+	_, _, _, err = convert("a %w", true, false)
+
+	assert.Error(test, err)
+
+	_, _, _, err = convert("a %5", true, false)
+
+	assert.Error(test, err)
+
+	message, arguments, _, err = convert("a %q %-5.2f", true, false)
+
+	assert.NoError(test, err)
+	assert.Equal(test, `a {printf "%q" p0} {printf "%-5.2f" p1}`, message)
+	assert.Equal(test, 2, arguments)
+
+	message, arguments, _, err = convert("a %d", false, false)
+
+	assert.NoError(test, err)
+	assert.Equal(test, "a %d", message)
+	assert.Zero(test, arguments)
+}
@@ -0,0 +1,88 @@
+// Code generated by rterror-gen from errors.yaml. DO NOT EDIT.
+
+package main
+
+import (
+	"time"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// These constants define error codes stored in the rterror.FieldCode field.
+const (
+	CodeUserNotFound   = "UserNotFound"
+	CodeRequestTimeout = "request.timeout"
+	CodeInternal       = "Internal"
+)
+
+// NewUserNotFound creates a new UserNotFound runtime error with location of the caller.
+//
+// User with provided identifier does not exist.
+//
+// Documentation: https://example.com/errors/user-not-found
+func NewUserNotFound(id int64) *rterror.RuntimeError {
+	return rterror.NewSkipCaller(rterror.SkipCall, "user {id} not found", map[string]interface{}{
+		"id": id,
+	}).SetKind("not_found").SetFields(map[string]interface{}{
+		rterror.FieldCode:       CodeUserNotFound,
+		rterror.FieldHTTPStatus: 404,
+		rterror.FieldDocsURL:    "https://example.com/errors/user-not-found",
+	})
+}
+
+// IsUserNotFound returns true if error chain contains the UserNotFound runtime error.
+func IsUserNotFound(err error) bool {
+	for _, r := range rterror.RuntimeErrors(err) {
+		if r.GetField(rterror.FieldCode) == CodeUserNotFound {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewRequestTimeout creates a new RequestTimeout runtime error with location of the caller.
+func NewRequestTimeout(method string, path string, timeout time.Duration) *rterror.RuntimeError {
+	return rterror.NewSkipCaller(rterror.SkipCall, "request {method} {path} timed out after {timeout}", map[string]interface{}{
+		"method":  method,
+		"path":    path,
+		"timeout": timeout,
+	}).SetKind("timeout").SetFields(map[string]interface{}{
+		rterror.FieldCode:       CodeRequestTimeout,
+		rterror.FieldHTTPStatus: 504,
+		rterror.FieldRetryable:  true,
+	})
+}
+
+// IsRequestTimeout returns true if error chain contains the RequestTimeout runtime error.
+func IsRequestTimeout(err error) bool {
+	for _, r := range rterror.RuntimeErrors(err) {
+		if r.GetField(rterror.FieldCode) == CodeRequestTimeout {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewInternal creates a new Internal runtime error with location of the caller.
+//
+// Unexpected internal error.
+// It is always reported with the wrapped cause.
+func NewInternal() *rterror.RuntimeError {
+	return rterror.NewSkipCaller(rterror.SkipCall, "internal error").SetFields(map[string]interface{}{
+		rterror.FieldCode:       CodeInternal,
+		rterror.FieldHTTPStatus: 500,
+	})
+}
+
+// IsInternal returns true if error chain contains the Internal runtime error.
+func IsInternal(err error) bool {
+	for _, r := range rterror.RuntimeErrors(err) {
+		if r.GetField(rterror.FieldCode) == CodeInternal {
+			return true
+		}
+	}
+
+	return false
+}
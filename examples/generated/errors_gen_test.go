@@ -0,0 +1,44 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func newHelperErrors() (errs []*rterror.RuntimeError, line int) {
+	_, _, line, _ = runtime.Caller(0)
+
+	return []*rterror.RuntimeError{
+		NewUserNotFound(5),
+		NewRequestTimeout("GET", "/users", time.Second),
+		NewInternal(),
+	}, line + 3
+}
+
+func TestGeneratedCaller(test *testing.T) {
+	errs, line := newHelperErrors()
+
+	for index, err := range errs {
+		assert.Equal(test, "gitlab.com/tymonx/go-error/examples/generated.newHelperErrors", err.Function())
+		assert.Equal(test, "errors_gen_test.go", err.FileBase())
+		assert.Equal(test, line+index, err.Line())
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:generate go run ../../cmd/rterror-gen -doc errors.md errors.yaml
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func find(id int64) error {
+	return NewUserNotFound(id)
+}
+
+func main() {
+	err := NewInternal().Wrap(find(5))
+
+	fmt.Println(err)
+	fmt.Println(IsUserNotFound(err), IsRequestTimeout(err))
+	fmt.Println(NewRequestTimeout("GET", "/users", 3*time.Second))
+}
@@ -14,9 +14,17 @@
 
 module gitlab.com/tymonx/go-error
 
-go 1.14
+go 1.20
 
 require (
 	github.com/stretchr/testify v1.6.1
 	gitlab.com/tymonx/go-formatter v1.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/mattn/go-isatty v0.0.12 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.0.0-20200116001909-b77594299b42 // indirect
 )
@@ -0,0 +1,23 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rtcheck implements static analyzer that checks usage of the rterror
+// package. It reports error message placeholders without matching arguments,
+// unknown fields and methods referenced by error formats, lost errors from
+// ignored Wrap() results and invalid NewSkipCaller() skip values in wrapper
+// functions. The analyzer can be used with the singlechecker package or with
+// the go vet command:
+//
+//  go vet -vettool=$(which rtcheck) ./...
+package rtcheck
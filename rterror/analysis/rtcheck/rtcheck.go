@@ -0,0 +1,337 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// PackagePath defines import path of the rterror package.
+const PackagePath = "gitlab.com/tymonx/go-error/rterror"
+
+// Analyzer checks usage of the rterror package.
+var Analyzer = &analysis.Analyzer{ // nolint: gochecknoglobals
+	Name:     "rtcheck",
+	Doc:      "check rterror message placeholders, formats, Wrap results and skip values",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// constructor defines positions of the skip and message arguments of rterror constructor.
+type constructor struct {
+	skip    int
+	message int
+}
+
+var gConstructors = map[string]constructor{ // nolint: gochecknoglobals
+	"New":              {skip: -1, message: 0},
+	"NewSkipCaller":    {skip: 0, message: 1},
+	"NewCtx":           {skip: -1, message: 1},
+	"NewCtxSkipCaller": {skip: 1, message: 2},
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	nodes := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	filter := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.ExprStmt)(nil),
+	}
+
+	nodes.WithStack(filter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		switch n := node.(type) {
+		case *ast.CallExpr:
+			checkCall(pass, n, stack)
+		case *ast.ExprStmt:
+			checkWrap(pass, n)
+		}
+
+		return true
+	})
+
+	return nil, nil
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, stack []ast.Node) {
+	function := callee(pass, call)
+
+	if function == nil {
+		return
+	}
+
+	if isMethod(function) {
+		if function.Name() == "SetFormat" {
+			checkFormat(pass, call, function)
+		}
+
+		return
+	}
+
+	if c, ok := gConstructors[function.Name()]; ok {
+		if !hasFormatter(stack) {
+			checkMessage(pass, call, function, c.message)
+		}
+
+		if c.skip >= 0 {
+			checkSkip(pass, call, function, c.skip, stack)
+		}
+	}
+}
+
+// checkMessage reports placeholders without matching arguments.
+func checkMessage(pass *analysis.Pass, call *ast.CallExpr, function *types.Func, position int) {
+	if (len(call.Args) <= position) || call.Ellipsis.IsValid() {
+		return
+	}
+
+	message, ok := constantString(pass, call.Args[position])
+
+	if !ok {
+		return
+	}
+
+	p, err := parsePlaceholders(message)
+
+	if err != nil {
+		pass.Reportf(call.Args[position].Pos(), "invalid %s message template: %v", function.Name(), err)
+		return
+	}
+
+	count := len(call.Args) - position - 1
+
+	for _, index := range p.positional {
+		if index >= count {
+			pass.Reportf(call.Args[position].Pos(), "%s message placeholder {p%d} has no matching argument, got %d arguments",
+				function.Name(), index, count)
+		}
+	}
+
+	if p.automatic > count {
+		pass.Reportf(call.Args[position].Pos(), "%s message uses %d automatic placeholders {p}, got %d arguments",
+			function.Name(), p.automatic, count)
+	}
+}
+
+// checkFormat reports fields and methods that runtime error does not have.
+func checkFormat(pass *analysis.Pass, call *ast.CallExpr, function *types.Func) {
+	if len(call.Args) != 1 {
+		return
+	}
+
+	format, ok := constantString(pass, call.Args[0])
+
+	if !ok {
+		return
+	}
+
+	p, err := parsePlaceholders(format)
+
+	if err != nil {
+		pass.Reportf(call.Args[0].Pos(), "invalid SetFormat format template: %v", err)
+		return
+	}
+
+	receiver := function.Type().(*types.Signature).Recv().Type()
+
+	for _, field := range p.fields {
+		if object, _, _ := types.LookupFieldOrMethod(receiver, true, function.Pkg(), field); object == nil {
+			pass.Reportf(call.Args[0].Pos(), "SetFormat format references unknown field or method {.%s} of %s",
+				field, types.TypeString(receiver, types.RelativeTo(pass.Pkg)))
+		}
+	}
+}
+
+// checkWrap reports ignored Wrap() result when wrapping runtime error is
+// created in the same expression and it is lost.
+func checkWrap(pass *analysis.Pass, statement *ast.ExprStmt) {
+	call, ok := astutil.Unparen(statement.X).(*ast.CallExpr)
+
+	if !ok {
+		return
+	}
+
+	function := callee(pass, call)
+
+	if (function == nil) || !isMethod(function) || (function.Name() != "Wrap") {
+		return
+	}
+
+	if selector, ok := astutil.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if _, ok := astutil.Unparen(selector.X).(*ast.CallExpr); ok {
+			pass.Reportf(call.Pos(), "result of Wrap is not used, wrapping error is lost")
+		}
+	}
+}
+
+// checkSkip reports invalid skip values. When runtime error is returned
+// from wrapper function, skip 0 records wrapper function instead of its caller.
+func checkSkip(pass *analysis.Pass, call *ast.CallExpr, function *types.Func, position int, stack []ast.Node) {
+	if len(call.Args) <= position {
+		return
+	}
+
+	value := pass.TypesInfo.Types[call.Args[position]].Value
+
+	if (value == nil) || (value.Kind() != constant.Int) {
+		return
+	}
+
+	skip, exact := constant.Int64Val(value)
+
+	switch {
+	case !exact || (skip < 0):
+		pass.Reportf(call.Args[position].Pos(), "%s skip value %s must not be negative", function.Name(), value)
+	case (skip == 0) && isReturned(stack) && isWrapper(pass, call, position, stack):
+		pass.Reportf(call.Args[position].Pos(),
+			"%s with skip 0 in wrapper function records wrapper instead of its caller, use rterror.SkipCall",
+			function.Name())
+	}
+}
+
+// isReturned returns true if call from the top of stack is returned from
+// function, directly or by chained method calls.
+func isReturned(stack []ast.Node) bool {
+	_, parent := methodChain(stack)
+
+	_, ok := parent.(*ast.ReturnStmt)
+
+	return ok
+}
+
+// isWrapper returns true if function that encloses call from the top of stack
+// passes its parameters to constructor as message or as variadic arguments.
+// Such function wraps constructor, so runtime error should record its caller.
+func isWrapper(pass *analysis.Pass, call *ast.CallExpr, position int, stack []ast.Node) bool {
+	var signature *ast.FuncType
+
+	for index := len(stack) - 2; (index >= 0) && (signature == nil); index-- {
+		switch n := stack[index].(type) {
+		case *ast.FuncDecl:
+			signature = n.Type
+		case *ast.FuncLit:
+			signature = n.Type
+		}
+	}
+
+	if signature == nil {
+		return false
+	}
+
+	parameters := make(map[types.Object]bool)
+
+	for _, field := range signature.Params.List {
+		for _, name := range field.Names {
+			parameters[pass.TypesInfo.Defs[name]] = true
+		}
+	}
+
+	forwarded := call.Args[position+1 : position+2]
+
+	if call.Ellipsis.IsValid() {
+		forwarded = append(forwarded, call.Args[len(call.Args)-1])
+	}
+
+	found := false
+
+	for _, argument := range forwarded {
+		ast.Inspect(argument, func(node ast.Node) bool {
+			if identifier, ok := node.(*ast.Ident); ok && parameters[pass.TypesInfo.Uses[identifier]] {
+				found = true
+			}
+
+			return !found
+		})
+	}
+
+	return found
+}
+
+// hasFormatter returns true if call from the top of stack is chained with
+// the SetFormatter() method. Custom message formatter can use different syntax.
+func hasFormatter(stack []ast.Node) bool {
+	methods, _ := methodChain(stack)
+
+	for _, method := range methods {
+		if method == "SetFormatter" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// methodChain returns names of methods chained after call from the top of
+// stack and the first node from stack that is not part of method chain.
+func methodChain(stack []ast.Node) (methods []string, parent ast.Node) {
+	child := stack[len(stack)-1]
+
+	for index := len(stack) - 2; index >= 0; index-- {
+		switch n := stack[index].(type) {
+		case *ast.SelectorExpr:
+			if n.X != child {
+				return methods, n
+			}
+
+			methods = append(methods, n.Sel.Name)
+		case *ast.CallExpr:
+			if n.Fun != child {
+				return methods, n
+			}
+		case *ast.ParenExpr:
+		default:
+			return methods, n
+		}
+
+		child = stack[index]
+	}
+
+	return methods, nil
+}
+
+func callee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	function, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+
+	if !ok || (function.Pkg() == nil) || (function.Pkg().Path() != PackagePath) {
+		return nil
+	}
+
+	return function
+}
+
+func isMethod(function *types.Func) bool {
+	return function.Type().(*types.Signature).Recv() != nil
+}
+
+func constantString(pass *analysis.Pass, expression ast.Expr) (string, bool) {
+	value := pass.TypesInfo.Types[expression].Value
+
+	if (value == nil) || (value.Kind() != constant.String) {
+		return "", false
+	}
+
+	return constant.StringVal(value), true
+}
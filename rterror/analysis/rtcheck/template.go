@@ -0,0 +1,110 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtcheck
+
+import (
+	"regexp"
+	"strconv"
+	"text/template/parse"
+
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+// placeholders defines placeholders used by error message or format.
+type placeholders struct {
+	automatic  int
+	positional []int
+	fields     []string
+}
+
+var gPositional = regexp.MustCompile(`^` + formatter.DefaultPlaceholder + `(\d+)$`) // nolint: gochecknoglobals
+
+// parsePlaceholders parses message using the same template syntax and
+// delimiters as the formatter object from the Go Formatter library.
+func parsePlaceholders(message string) (*placeholders, error) {
+	tree := parse.New("message")
+	tree.Mode = parse.SkipFuncCheck
+
+	if _, err := tree.Parse(message, formatter.DefaultLeftDelimiter, formatter.DefaultRightDelimiter,
+		make(map[string]*parse.Tree)); err != nil {
+		return nil, err
+	}
+
+	p := new(placeholders)
+
+	p.walk(tree.Root, true)
+
+	return p, nil
+}
+
+// walk collects placeholders from node. Fields are collected only when dot is
+// the template object, outside of range and with actions.
+func (p *placeholders) walk(node parse.Node, dot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n != nil {
+			for _, child := range n.Nodes {
+				p.walk(child, dot)
+			}
+		}
+	case *parse.ActionNode:
+		p.walk(n.Pipe, dot)
+	case *parse.PipeNode:
+		if n != nil {
+			for _, command := range n.Cmds {
+				p.walk(command, dot)
+			}
+		}
+	case *parse.CommandNode:
+		for _, argument := range n.Args {
+			p.walk(argument, dot)
+		}
+	case *parse.ChainNode:
+		p.walk(n.Node, dot)
+	case *parse.IdentifierNode:
+		p.identifier(n.Ident)
+	case *parse.FieldNode:
+		if dot {
+			p.fields = append(p.fields, n.Ident[0])
+		}
+	case *parse.IfNode:
+		p.walk(n.Pipe, dot)
+		p.walk(n.List, dot)
+		p.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		p.walk(n.Pipe, dot)
+		p.walk(n.List, false)
+		p.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		p.walk(n.Pipe, dot)
+		p.walk(n.List, false)
+		p.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		p.walk(n.Pipe, dot)
+	}
+}
+
+func (p *placeholders) identifier(name string) {
+	if name == formatter.DefaultPlaceholder {
+		p.automatic++
+		return
+	}
+
+	if match := gPositional.FindStringSubmatch(name); match != nil {
+		if position, err := strconv.Atoi(match[1]); err == nil {
+			p.positional = append(p.positional, position)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package a
+
+import (
+	"context"
+	"errors"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+type CustomError struct {
+	*rterror.RuntimeError
+}
+
+func messages(ctx context.Context, arguments []interface{}) {
+	_ = rterror.New("valid {p0} {p1}", 1, 2)
+	_ = rterror.New("valid {p} {p}", 1, 2)
+	_ = rterror.New("valid {p}", 1, 2)
+	_ = rterror.New("valid {name}", map[string]interface{}{"name": 1})
+	_ = rterror.New("valid {p3}", arguments...)
+	_ = rterror.New("invalid {p1}", 1)            // want `New message placeholder \{p1\} has no matching argument, got 1 arguments`
+	_ = rterror.New("invalid {p} {p}", 1)         // want `New message uses 2 automatic placeholders \{p\}, got 1 arguments`
+	_ = rterror.New("invalid {p0")                // want `invalid New message template`
+	_ = rterror.NewSkipCaller(1, "invalid {p0}")  // want `NewSkipCaller message placeholder \{p0\} has no matching argument, got 0 arguments`
+	_ = rterror.NewCtx(ctx, "invalid {p2}", 1, 2) // want `NewCtx message placeholder \{p2\} has no matching argument, got 2 arguments`
+	_ = rterror.NewCtxSkipCaller(ctx, 1, "{p0}", 1)
+	_ = rterror.New("valid %d %d", 1, 2).SetFormatter(rterror.SprintfFormatter{})
+	_ = rterror.New("valid {{.p1}}", 1, 2).SetFormatter(nil)
+	_ = rterror.New("valid %d %d", 1, 2).SetKind("kind").SetFormatter(rterror.SprintfFormatter{})
+	_ = (rterror.New("valid %s", 1).SetKind("kind")).SetFormatter(nil)
+	_ = rterror.New("invalid {p1}", 1).SetKind("kind")              // want `New message placeholder \{p1\} has no matching argument, got 1 arguments`
+	_ = formatted(rterror.New("invalid {p1}", 1)).SetFormatter(nil) // want `New message placeholder \{p1\} has no matching argument, got 1 arguments`
+}
+
+func formatted(err *rterror.RuntimeError) *rterror.RuntimeError {
+	return err
+}
+
+func formats() {
+	_ = rterror.New("valid").SetFormat("{.Package}:{.FileBase}:{.Line}: {.String}")
+	_ = rterror.New("valid").SetFormat("{with .String}{.Length}{end}")
+	_ = rterror.New("invalid").SetFormat("{.Unknown}: {.String}") // want `SetFormat format references unknown field or method \{\.Unknown\} of \*gitlab.com/tymonx/go-error/rterror.RuntimeError`
+	_ = CustomError{rterror.New("invalid")}.SetFormat("{.Lines}") // want `unknown field or method \{\.Lines\}`
+}
+
+func wraps(err *rterror.RuntimeError) {
+	err.Wrap(errors.New("valid"))
+	_ = rterror.New("valid").Wrap(err)
+	rterror.New("invalid").Wrap(err) // want `result of Wrap is not used, wrapping error is lost`
+}
+
+func NewWrapper(message string) *rterror.RuntimeError {
+	return rterror.NewSkipCaller(rterror.SkipCall, message)
+}
+
+func NewInvalidWrapper(message string) error {
+	return rterror.NewSkipCaller(0, message).Wrap(nil) // want `NewSkipCaller with skip 0 in wrapper function records wrapper instead of its caller, use rterror.SkipCall`
+}
+
+func NewInvalidClosure() func(...interface{}) error {
+	return func(arguments ...interface{}) error {
+		return rterror.NewSkipCaller(0, "code {p0}", arguments...) // want `NewSkipCaller with skip 0 in wrapper function records wrapper instead of its caller, use rterror.SkipCall`
+	}
+}
+
+func validate(message string) error {
+	if message == "" {
+		return rterror.NewSkipCaller(0, "valid").SetKind("validation")
+	}
+
+	return nil
+}
+
+func skips(ctx context.Context, code int) error {
+	_ = rterror.NewSkipCaller(0, "valid")
+	_ = func() error {
+		return rterror.NewSkipCaller(0, "valid {p0}", code)
+	}
+
+	_ = rterror.NewCtxSkipCaller(ctx, -1, "invalid") // want `NewCtxSkipCaller skip value -1 must not be negative`
+
+	return rterror.NewCtxSkipCaller(ctx, 0, "valid {p0}", code)
+}
@@ -0,0 +1,40 @@
+// Package rterror is a stub of the rterror package used by analyzer tests.
+package rterror
+
+import "context"
+
+const SkipCall = 1
+
+type Kind string
+
+type RuntimeError struct {
+	err error
+}
+
+func New(message string, arguments ...interface{}) *RuntimeError { return &RuntimeError{} }
+
+func NewSkipCaller(skip int, message string, arguments ...interface{}) *RuntimeError {
+	return &RuntimeError{}
+}
+
+func NewCtx(ctx context.Context, message string, arguments ...interface{}) *RuntimeError {
+	return &RuntimeError{}
+}
+
+func NewCtxSkipCaller(ctx context.Context, skip int, message string, arguments ...interface{}) *RuntimeError {
+	return &RuntimeError{}
+}
+
+func (r *RuntimeError) Line() int                             { return 0 }
+func (r *RuntimeError) FileBase() string                      { return "" }
+func (r *RuntimeError) FunctionBase() string                  { return "" }
+func (r *RuntimeError) Package() string                       { return "" }
+func (r *RuntimeError) String() string                        { return "" }
+func (r *RuntimeError) Error() string                         { return "" }
+func (r *RuntimeError) SetFormat(format string) *RuntimeError { return r }
+func (r *RuntimeError) Wrap(err error) *RuntimeError          { r.err = err; return r }
+func (r *RuntimeError) SetKind(kind Kind) *RuntimeError       { return r }
+
+type SprintfFormatter struct{}
+
+func (r *RuntimeError) SetFormatter(f interface{}) *RuntimeError { return r }
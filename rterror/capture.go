@@ -0,0 +1,128 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock defines a function that returns current time.
+type Clock func() time.Time
+
+var (
+	gTime  int32        // nolint: gochecknoglobals
+	gID    int32        // nolint: gochecknoglobals
+	gClock atomic.Value // nolint: gochecknoglobals
+)
+
+// SetTime enables or disables capturing of creation time for new runtime errors.
+func SetTime(enabled bool) {
+	atomic.StoreInt32(&gTime, boolToInt32(enabled))
+}
+
+// EnableTime enables capturing of creation time for new runtime errors.
+func EnableTime() {
+	SetTime(true)
+}
+
+// DisableTime disables capturing of creation time for new runtime errors.
+// It is disabled by default.
+func DisableTime() {
+	SetTime(false)
+}
+
+// IsTimeEnabled returns true if capturing of creation time is enabled. Otherwise, it returns false.
+func IsTimeEnabled() bool {
+	return atomic.LoadInt32(&gTime) != 0
+}
+
+// SetID enables or disables generating of unique identifiers for new runtime errors.
+func SetID(enabled bool) {
+	atomic.StoreInt32(&gID, boolToInt32(enabled))
+}
+
+// EnableID enables generating of unique identifiers for new runtime errors.
+func EnableID() {
+	SetID(true)
+}
+
+// DisableID disables generating of unique identifiers for new runtime errors.
+// It is disabled by default.
+func DisableID() {
+	SetID(false)
+}
+
+// IsIDEnabled returns true if generating of unique identifiers is enabled. Otherwise, it returns false.
+func IsIDEnabled() bool {
+	return atomic.LoadInt32(&gID) != 0
+}
+
+// SetClock sets clock used to capture creation time and to generate unique identifiers.
+func SetClock(clock Clock) {
+	gClock.Store(clock)
+}
+
+// GetClock returns clock used to capture creation time and to generate unique identifiers.
+func GetClock() Clock {
+	if clock, ok := gClock.Load().(Clock); ok && (clock != nil) {
+		return clock
+	}
+
+	return time.Now
+}
+
+// ResetClock resets clock to default value that is the time.Now() function.
+func ResetClock() {
+	SetClock(time.Now)
+}
+
+// Time returns runtime error creation time. It returns zero time if
+// capturing of creation time was disabled when runtime error was created.
+func (r *RuntimeError) Time() time.Time {
+	return r.time
+}
+
+// ID returns runtime error unique identifier. It returns empty string if
+// generating of unique identifiers was disabled when runtime error was created.
+func (r *RuntimeError) ID() string {
+	return r.id
+}
+
+func (r *RuntimeError) capture() {
+	timeEnabled, idEnabled := IsTimeEnabled(), IsIDEnabled()
+
+	if !timeEnabled && !idEnabled {
+		return
+	}
+
+	now := GetClock()()
+
+	if timeEnabled {
+		r.time = now
+	}
+
+	if idEnabled {
+		r.id = newID(now)
+	}
+}
+
+func boolToInt32(value bool) int32 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+var testTime = time.Date(2021, time.March, 4, 5, 6, 7, 0, time.UTC) // nolint: gochecknoglobals
+
+func setCapture(test *testing.T) {
+	rterror.EnableTime()
+	rterror.EnableID()
+	rterror.SetClock(func() time.Time {
+		return testTime
+	})
+
+	test.Cleanup(func() {
+		rterror.DisableTime()
+		rterror.DisableID()
+		rterror.ResetClock()
+	})
+}
+
+func TestCaptureDisabled(test *testing.T) {
+	err := rterror.New("error")
+
+	assert.False(test, rterror.IsTimeEnabled())
+	assert.False(test, rterror.IsIDEnabled())
+	assert.True(test, err.Time().IsZero())
+	assert.Empty(test, err.ID())
+}
+
+func TestCaptureTime(test *testing.T) {
+	setCapture(test)
+
+	assert.True(test, rterror.IsTimeEnabled())
+	assert.Equal(test, testTime, rterror.New("error").Time())
+}
+
+func TestCaptureID(test *testing.T) {
+	setCapture(test)
+
+	ids := make([]string, 100)
+
+	for index := range ids {
+		ids[index] = rterror.New("error").ID()
+	}
+
+	assert.True(test, rterror.IsIDEnabled())
+	assert.Len(test, ids[0], rterror.IDSize)
+	assert.True(test, sort.StringsAreSorted(ids))
+
+	for index := 1; index < len(ids); index++ {
+		assert.NotEqual(test, ids[index-1], ids[index])
+	}
+}
+
+func TestCaptureIDTimestamp(test *testing.T) {
+	setCapture(test)
+
+	first := rterror.New("error").ID()
+
+	rterror.SetClock(func() time.Time {
+		return testTime.Add(time.Millisecond)
+	})
+
+	second := rterror.New("error").ID()
+
+	assert.Equal(test, "01EZXT1YGR", first[:10])
+	assert.Equal(test, "01EZXT1YGS", second[:10])
+}
+
+func TestCaptureFormat(test *testing.T) {
+	setCapture(test)
+
+	err := rterror.New("error")
+
+	assert.Equal(test, "2021-03-04T05:06:07Z "+err.ID(), err.SetFormat("{.Time | iso8601} {.ID}").Error())
+}
+
+func TestCaptureMarshalJSON(test *testing.T) {
+	setCapture(test)
+
+	var decoded struct {
+		Time time.Time `json:"time"`
+		ID   string    `json:"id"`
+	}
+
+	err := rterror.New("error")
+	data, e := json.Marshal(err)
+
+	assert.NoError(test, e)
+	assert.NoError(test, json.Unmarshal(data, &decoded))
+	assert.Equal(test, testTime, decoded.Time)
+	assert.Equal(test, err.ID(), decoded.ID)
+}
@@ -0,0 +1,321 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// These constants define messages of errors returned by collector and group.
+const (
+	CollectorMessage         = "Collected {p0} errors"
+	CollectorOverflowMessage = "Collected {p0} errors, {p1} errors were dropped"
+	PanicMessage             = "Goroutine panicked: {p0}"
+)
+
+// KindPanic defines kind of runtime errors created from recovered panics.
+const KindPanic Kind = "panic"
+
+// MaxPanicDepth defines maximum number of stack frames searched for function that panicked.
+const MaxPanicDepth = 16
+
+// Collector defines an aggregator of errors that is safe for concurrent use.
+// It stores errors and error groups up to the limit and counts dropped errors.
+// With grouping, it stores only the first error for each fingerprint.
+type Collector struct {
+	mutex     sync.Mutex
+	pc        [1]uintptr
+	limit     int
+	grouping  bool
+	total     int
+	ungrouped int
+	errors    []error
+	groups    map[string]*ErrorGroup
+	order     []string
+}
+
+// ErrorGroup defines errors with the same fingerprint added to collector.
+type ErrorGroup struct {
+	Fingerprint string
+	Err         error
+	Count       int
+}
+
+// Group defines a group of goroutines that collects all returned errors and
+// recovered panics. Unlike the errgroup package, it doesn't stop on the first error.
+type Group struct {
+	wait      sync.WaitGroup
+	collector *Collector
+}
+
+// NewCollector creates a new collector object. It records line number, file
+// path and function name from where the NewCollector() function was called.
+// The Err() method returns runtime error with this origin.
+func NewCollector() *Collector {
+	return newCollector(SkipCall)
+}
+
+// NewGroup creates a new group object with collector that records line number,
+// file path and function name from where the NewGroup() function was called.
+func NewGroup() *Group {
+	return &Group{
+		collector: newCollector(SkipCall),
+	}
+}
+
+// SetLimit sets maximum number of stored errors and error groups. Errors above
+// the limit and errors with new fingerprint above the limit of error groups are
+// dropped and counted. Zero means no limit.
+func (c *Collector) SetLimit(limit int) *Collector {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.limit = limit
+
+	return c
+}
+
+// GetLimit returns maximum number of stored errors and error groups.
+func (c *Collector) GetLimit() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.limit
+}
+
+// SetGrouping enables or disables grouping. With grouping, only the first
+// error for each fingerprint is stored. Errors are always counted by fingerprint.
+func (c *Collector) SetGrouping(enabled bool) *Collector {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.grouping = enabled
+
+	return c
+}
+
+// EnableGrouping enables grouping.
+func (c *Collector) EnableGrouping() *Collector {
+	return c.SetGrouping(true)
+}
+
+// DisableGrouping disables grouping.
+func (c *Collector) DisableGrouping() *Collector {
+	return c.SetGrouping(false)
+}
+
+// IsGroupingEnabled returns true if grouping is enabled. Otherwise, it returns false.
+func (c *Collector) IsGroupingEnabled() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.grouping
+}
+
+// Add adds error to collector. Nil error is ignored. It is safe for concurrent use.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	fingerprint := fingerprintOf(err)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.total++
+
+	group, grouped := c.groups[fingerprint]
+
+	if !grouped {
+		if (c.limit > 0) && (len(c.order) >= c.limit) {
+			c.ungrouped++
+			return
+		}
+
+		group = &ErrorGroup{
+			Fingerprint: fingerprint,
+			Err:         err,
+		}
+
+		if c.groups == nil {
+			c.groups = make(map[string]*ErrorGroup)
+		}
+
+		c.groups[fingerprint] = group
+		c.order = append(c.order, fingerprint)
+	}
+
+	group.Count++
+
+	if (c.grouping && grouped) || ((c.limit > 0) && (len(c.errors) >= c.limit)) {
+		return
+	}
+
+	c.errors = append(c.errors, err)
+}
+
+// Len returns number of all added errors including not stored errors.
+func (c *Collector) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.total
+}
+
+// Overflow returns number of errors dropped because of the limit. Errors
+// not stored because of grouping are not counted, errors with fingerprint
+// above the limit of error groups are counted.
+func (c *Collector) Overflow() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.overflow()
+}
+
+// Errors returns a copy of stored errors in order they were added.
+func (c *Collector) Errors() []error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]error(nil), c.errors...)
+}
+
+// Groups returns a copy of error groups in order of their first errors.
+func (c *Collector) Groups() []ErrorGroup {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	groups := make([]ErrorGroup, 0, len(c.order))
+
+	for _, fingerprint := range c.order {
+		groups = append(groups, *c.groups[fingerprint])
+	}
+
+	return groups
+}
+
+// Err returns nil if no errors were added. Otherwise, it returns runtime error
+// that wraps all stored errors joined with the errors.Join() function, so the
+// errors.Is() and errors.As() functions match any of them. Runtime error origin
+// is from where collector was created.
+func (c *Collector) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.total == 0 {
+		return nil
+	}
+
+	var r *RuntimeError
+
+	if overflow := c.overflow(); overflow > 0 {
+		r = newRuntimeError(c.pc, CollectorOverflowMessage, c.total, overflow)
+	} else {
+		r = newRuntimeError(c.pc, CollectorMessage, c.total)
+	}
+
+	return r.Wrap(errors.Join(c.errors...))
+}
+
+// Go runs function in new goroutine. Returned error and recovered panic are
+// added to group collector. Recovered panic becomes runtime error with KindPanic kind.
+func (g *Group) Go(function func() error) {
+	g.wait.Add(1)
+
+	go func() {
+		defer g.wait.Done()
+		defer g.recover()
+
+		g.collector.Add(function())
+	}()
+}
+
+// Wait waits for all goroutines and returns the Err() method result of group collector.
+func (g *Group) Wait() error {
+	g.wait.Wait()
+
+	return g.collector.Err()
+}
+
+// Collector returns group collector. It can be used to set limit and grouping.
+func (g *Group) Collector() *Collector {
+	return g.collector
+}
+
+func (g *Group) recover() {
+	value := recover()
+
+	if value == nil {
+		return
+	}
+
+	r := newRuntimeError(panicCaller(), PanicMessage, value).SetKind(KindPanic)
+
+	if err, ok := value.(error); ok {
+		r.Wrap(err)
+	}
+
+	g.collector.Add(r)
+}
+
+func (c *Collector) overflow() int {
+	if c.grouping {
+		return c.ungrouped
+	}
+
+	return c.total - len(c.errors)
+}
+
+// panicCaller returns program counter of function that panicked. It skips
+// frames of the runtime package that raised panic and of the Group.recover() method.
+func panicCaller() (pc [1]uintptr) {
+	var pcs [MaxPanicDepth]uintptr
+
+	for _, caller := range pcs[:runtime.Callers(SkipCall+SkipCall+SkipCall, pcs[:])] {
+		frame, _ := runtime.CallersFrames([]uintptr{caller}).Next()
+
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			pc[0] = caller
+			return pc
+		}
+	}
+
+	return pc
+}
+
+func newCollector(skip int) *Collector {
+	c := &Collector{}
+
+	runtime.Callers((SkipCall + SkipCall + skip), c.pc[:])
+
+	return c
+}
+
+// fingerprintOf returns fingerprint of the outermost runtime error in error
+// chain or fingerprint computed from error type and message for other errors.
+func fingerprintOf(err error) string {
+	var r *RuntimeError
+
+	if errors.As(err, &r) {
+		return r.Fingerprint()
+	}
+
+	return FingerprintOf(fmt.Sprintf("%T", err), err.Error())
+}
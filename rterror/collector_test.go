@@ -0,0 +1,211 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func TestCollector(test *testing.T) {
+	c := rterror.NewCollector()
+
+	assert.NoError(test, c.Err())
+
+	c.Add(nil)
+	c.Add(os.ErrNotExist)
+	c.Add(rterror.New("error {p0}", 1))
+
+	err := c.Err()
+
+	var r *rterror.RuntimeError
+
+	assert.True(test, errors.As(err, &r))
+	assert.Equal(test, "Collected 2 errors", r.String())
+	assert.Equal(test, "TestCollector", r.FunctionBase())
+	assert.True(test, errors.Is(err, os.ErrNotExist))
+	assert.Len(test, rterror.RuntimeErrors(err), 2)
+	assert.Equal(test, 2, c.Len())
+	assert.Zero(test, c.Overflow())
+	assert.Len(test, c.Errors(), 2)
+}
+
+func TestCollectorRender(test *testing.T) {
+	c := rterror.NewCollector()
+
+	c.Add(rterror.New("A").SetFormat("{.String}").Wrap(errors.New("B")))
+	c.Add(errors.New("C"))
+	c.Add(rterror.New("D").SetFormat("{.String}").Wrap(errors.Join(errors.New("E"), errors.New("F"))))
+
+	var r *rterror.RuntimeError
+
+	assert.True(test, errors.As(c.Err(), &r))
+
+	r.SetFormat("{.String}")
+
+	expected := "Collected 3 errors\n`--A\n   `--B\n`--C\n`--D\n   `--E\n   `--F"
+
+	var builder strings.Builder
+
+	_, err := r.WriteTo(&builder)
+
+	assert.NoError(test, err)
+	assert.Equal(test, expected, r.Error())
+	assert.Equal(test, expected, builder.String())
+	assert.Equal(test, "1. Collected 3 errors\n2. A\n3. B\n4. C\n5. D\n6. E\n7. F",
+		r.SetRenderer(rterror.NewRenderer(rterror.StyleNumbered)).Error())
+}
+
+func TestCollectorLimit(test *testing.T) {
+	c := rterror.NewCollector().SetLimit(2)
+
+	for index := 0; index < 5; index++ {
+		c.Add(rterror.New("error {p0}", index))
+	}
+
+	assert.Equal(test, 2, c.GetLimit())
+	assert.Equal(test, 5, c.Len())
+	assert.Equal(test, 3, c.Overflow())
+	assert.Len(test, c.Errors(), 2)
+	assert.Equal(test, "Collected 5 errors, 3 errors were dropped", rterror.RuntimeErrors(c.Err())[0].String())
+}
+
+func TestCollectorGrouping(test *testing.T) {
+	c := rterror.NewCollector().EnableGrouping()
+
+	for index := 0; index < 3; index++ {
+		c.Add(rterror.New("first {p0}", index))
+		c.Add(rterror.New("second {p0}", index))
+	}
+
+	c.Add(errors.New("other"))
+	c.Add(errors.New("other"))
+
+	groups := c.Groups()
+
+	assert.True(test, c.IsGroupingEnabled())
+	assert.Equal(test, 8, c.Len())
+	assert.Zero(test, c.Overflow())
+	assert.Len(test, c.Errors(), 3)
+	assert.Len(test, groups, 3)
+	assert.Equal(test, 3, groups[0].Count)
+	assert.Equal(test, "first 0", groups[0].Err.(*rterror.RuntimeError).String())
+	assert.Equal(test, groups[0].Err.(*rterror.RuntimeError).Fingerprint(), groups[0].Fingerprint)
+	assert.Equal(test, 3, groups[1].Count)
+	assert.Equal(test, 2, groups[2].Count)
+	assert.False(test, c.DisableGrouping().IsGroupingEnabled())
+}
+
+func TestCollectorGroupingLimit(test *testing.T) {
+	c := rterror.NewCollector().SetLimit(2)
+
+	for index := 0; index < 100; index++ {
+		c.Add(fmt.Errorf("dynamic %d", index))
+	}
+
+	assert.Equal(test, 100, c.Len())
+	assert.Equal(test, 98, c.Overflow())
+	assert.Len(test, c.Groups(), 2)
+	assert.Len(test, c.Errors(), 2)
+
+	c.EnableGrouping()
+	c.Add(fmt.Errorf("dynamic %d", 0))
+	c.Add(fmt.Errorf("dynamic %d", 100))
+
+	groups := c.Groups()
+
+	assert.Equal(test, 99, c.Overflow())
+	assert.Len(test, groups, 2)
+	assert.Equal(test, 2, groups[0].Count)
+	assert.Len(test, c.Errors(), 2)
+}
+
+func TestCollectorConcurrent(test *testing.T) {
+	var wait sync.WaitGroup
+
+	c := rterror.NewCollector().SetLimit(10)
+
+	for index := 0; index < 100; index++ {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+			c.Add(rterror.New("error"))
+		}()
+	}
+
+	wait.Wait()
+
+	assert.Equal(test, 100, c.Len())
+	assert.Equal(test, 90, c.Overflow())
+	assert.Len(test, c.Groups(), 1)
+	assert.Equal(test, 100, c.Groups()[0].Count)
+}
+
+func TestGroup(test *testing.T) {
+	g := rterror.NewGroup()
+
+	g.Go(func() error {
+		return nil
+	})
+
+	g.Go(func() error {
+		return os.ErrClosed
+	})
+
+	g.Go(func() error {
+		panic(os.ErrPermission)
+	})
+
+	g.Go(func() error {
+		panic("boom")
+	})
+
+	err := g.Wait()
+
+	var panics []*rterror.RuntimeError
+
+	for _, r := range rterror.RuntimeErrors(err) {
+		if r.GetKind() == rterror.KindPanic {
+			panics = append(panics, r)
+		}
+	}
+
+	assert.Equal(test, "Collected 3 errors", rterror.RuntimeErrors(err)[0].String())
+	assert.Equal(test, "TestGroup", rterror.RuntimeErrors(err)[0].FunctionBase())
+	assert.True(test, errors.Is(err, os.ErrClosed))
+	assert.True(test, errors.Is(err, os.ErrPermission))
+	assert.True(test, errors.Is(err, rterror.KindPanic))
+	assert.Len(test, panics, 2)
+	assert.Contains(test, []string{panics[0].FunctionBase(), panics[1].FunctionBase()}, "TestGroup.func4")
+	assert.Equal(test, 3, g.Collector().Len())
+}
+
+func TestGroupEmpty(test *testing.T) {
+	g := rterror.NewGroup()
+
+	g.Go(func() error {
+		return nil
+	})
+
+	assert.NoError(test, g.Wait())
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// Frame defines a program counter of stack frame. It has the same layout
+// and formatting as the Frame type from the github.com/pkg/errors package but
+// it is a distinct type.
+type Frame uintptr
+
+// StackTrace defines a stack of frames from innermost to outermost. It has the
+// same layout and formatting as the StackTrace type from the github.com/pkg/errors
+// package but it is a distinct type.
+type StackTrace []Frame
+
+// Printer defines an interface with the same methods as the Printer interface
+// from the golang.org/x/xerrors package. Any xerrors printer can be passed to
+// the FormatError() method. Because method signatures differ, runtime error
+// does not implement the xerrors.Formatter interface itself.
+type Printer interface {
+	Print(args ...interface{})
+	Printf(format string, args ...interface{})
+	Detail() bool
+}
+
+type statePrinter struct {
+	state  fmt.State
+	detail bool
+}
+
+// Cause returns wrapped error like errors created by the github.com/pkg/errors
+// package. It returns nil if there is no wrapped error.
+func (r *RuntimeError) Cause() error {
+	return r.err
+}
+
+// StackTrace returns stack trace with frame from where runtime error was created.
+// It allows tools that detect the StackTrace() method of the github.com/pkg/errors
+// package errors with reflection, like the Sentry Go SDK, to pick up runtime error
+// frames. Returned type differs from the github.com/pkg/errors StackTrace type,
+// runtime error does not satisfy interfaces declared with that type.
+func (r *RuntimeError) StackTrace() StackTrace {
+	return StackTrace{Frame(r.pc[0])}
+}
+
+// FormatError prints runtime error to printer like errors implementing the
+// Formatter interface from the golang.org/x/xerrors package. With detail,
+// it also prints function name, file path and line number. It returns wrapped
+// error. Runtime error does not implement the xerrors.Formatter interface,
+// custom error types can implement it with the adapter method:
+//
+//  func (e *MyError) FormatError(p xerrors.Printer) error {
+//      return e.RuntimeError.FormatError(p)
+//  }
+func (r *RuntimeError) FormatError(p Printer) (next error) {
+	p.Print(r.String())
+
+	if p.Detail() {
+		l := r.locator()
+
+		p.Printf("\n%s\n\t%s:%d", r.Function(), l.File(), l.Line())
+	}
+
+	return r.err
+}
+
+// Format formats runtime error. The %+v verb prints each error from error
+// chain with function name, file path and line number like errors created by
+// the github.com/pkg/errors package. Other verbs, flags, width and precision
+// format output of the Error() method like for any other error, for example
+// the %s and %v verbs print it as is, the %q verb prints it quoted and the %x
+// verb prints it hex-encoded.
+func (r *RuntimeError) Format(state fmt.State, verb rune) {
+	if (verb == 'v') && state.Flag('+') {
+		r.formatDetail(state)
+		return
+	}
+
+	_, _ = fmt.Fprintf(state, fmt.FormatString(state, verb), r.Error())
+}
+
+// Format formats stack frame like the Frame type from the github.com/pkg/errors package:
+//
+//  %s    file base name
+//  %d    line number
+//  %n    function base name
+//  %v    equivalent to %s:%d
+//  %+s   function name and file path separated by new line and tab
+//  %+v   equivalent to %+s:%d
+func (f Frame) Format(state fmt.State, verb rune) {
+	frame := f.frame()
+
+	switch verb {
+	case 's':
+		if state.Flag('+') {
+			_, _ = io.WriteString(state, frame.Function+"\n\t"+frame.File)
+		} else {
+			_, _ = io.WriteString(state, filepath.Base(frame.File))
+		}
+	case 'd':
+		_, _ = io.WriteString(state, strconv.Itoa(frame.Line))
+	case 'n':
+		_, _ = io.WriteString(state, functionBase(frame.Function))
+	case 'v':
+		f.Format(state, 's')
+		_, _ = io.WriteString(state, ":")
+		f.Format(state, 'd')
+	}
+}
+
+// Format formats stack trace like the StackTrace type from the github.com/pkg/errors
+// package. The %+v verb prints each frame in new line with the %+v verb.
+func (s StackTrace) Format(state fmt.State, verb rune) {
+	switch {
+	case (verb == 'v') && state.Flag('+'):
+		for _, f := range s {
+			_, _ = io.WriteString(state, "\n")
+			f.Format(state, verb)
+		}
+	case (verb == 'v') || (verb == 's'):
+		_, _ = io.WriteString(state, "[")
+
+		for index, f := range s {
+			if index != 0 {
+				_, _ = io.WriteString(state, " ")
+			}
+
+			f.Format(state, verb)
+		}
+
+		_, _ = io.WriteString(state, "]")
+	}
+}
+
+func (r *RuntimeError) formatDetail(state fmt.State) {
+	var err error = r
+
+	for err != nil {
+		e, ok := err.(*RuntimeError)
+
+		if !ok {
+			_, _ = fmt.Fprintf(state, "%+v", err)
+			return
+		}
+
+		err = e.FormatError(&statePrinter{
+			state:  state,
+			detail: true,
+		})
+
+		if err != nil {
+			_, _ = io.WriteString(state, "\n")
+		}
+	}
+}
+
+// frame returns stack frame. In deterministic mode, line number and file path
+// are replaced with stable placeholders.
+func (f Frame) frame() runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{uintptr(f)}).Next()
+
+	if IsDeterministicEnabled() {
+		frame.Line = DeterministicLine
+		frame.File = DeterministicPath + "/" + filepath.Base(frame.File)
+	}
+
+	return frame
+}
+
+func (p *statePrinter) Print(args ...interface{}) {
+	_, _ = fmt.Fprint(p.state, args...)
+}
+
+func (p *statePrinter) Printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(p.state, format, args...)
+}
+
+func (p *statePrinter) Detail() bool {
+	return p.detail
+}
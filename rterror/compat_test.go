@@ -0,0 +1,161 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// causer defines the same interface as the github.com/pkg/errors package uses.
+type causer interface {
+	Cause() error
+}
+
+// printer defines the same interface as the golang.org/x/xerrors package Printer.
+type printer interface {
+	Print(args ...interface{})
+	Printf(format string, args ...interface{})
+	Detail() bool
+}
+
+// xerrorsFormatter defines the same interface as the golang.org/x/xerrors package
+// Formatter but with local printer interface, like custom error types adapt it.
+type xerrorsFormatter interface {
+	FormatError(p printer) (next error)
+}
+
+type myError struct {
+	rterror.RuntimeError
+}
+
+type bufferPrinter struct {
+	strings.Builder
+	detail bool
+}
+
+func (e *myError) FormatError(p printer) error {
+	return e.RuntimeError.FormatError(p)
+}
+
+func (p *bufferPrinter) Print(args ...interface{}) {
+	fmt.Fprint(p, args...)
+}
+
+func (p *bufferPrinter) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(p, format, args...)
+}
+
+func (p *bufferPrinter) Detail() bool {
+	return p.detail
+}
+
+// extractPCs extracts program counters like the Sentry Go SDK does it for
+// the github.com/pkg/errors package errors.
+func extractPCs(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+
+	if !method.IsValid() {
+		return nil
+	}
+
+	stacktrace := method.Call(nil)[0]
+
+	if stacktrace.Kind() != reflect.Slice {
+		return nil
+	}
+
+	pcs := make([]uintptr, 0, stacktrace.Len())
+
+	for index := 0; index < stacktrace.Len(); index++ {
+		if pc := stacktrace.Index(index); pc.Kind() == reflect.Uintptr {
+			pcs = append(pcs, uintptr(pc.Uint()))
+		}
+	}
+
+	return pcs
+}
+
+func TestCompatCause(test *testing.T) {
+	wrapped := errors.New("wrapped")
+	err := rterror.New("error").Wrap(wrapped)
+
+	var c causer = err
+
+	assert.Equal(test, wrapped, c.Cause())
+	assert.Nil(test, rterror.New("error").Cause())
+}
+
+func TestCompatStackTrace(test *testing.T) {
+	err := rterror.New("error")
+	pcs := extractPCs(err)
+
+	assert.Len(test, pcs, 1)
+
+	frame, _ := runtime.CallersFrames(pcs).Next()
+
+	assert.Equal(test, err.Function(), frame.Function)
+	assert.Equal(test, err.File(), frame.File)
+	assert.Equal(test, err.Line(), frame.Line)
+
+	stack := err.StackTrace()
+	line := fmt.Sprint(err.Line())
+
+	assert.Equal(test, "compat_test.go", fmt.Sprintf("%s", stack[0]))
+	assert.Equal(test, line, fmt.Sprintf("%d", stack[0]))
+	assert.Equal(test, "TestCompatStackTrace", fmt.Sprintf("%n", stack[0]))
+	assert.Equal(test, "compat_test.go:"+line, fmt.Sprintf("%v", stack[0]))
+	assert.Equal(test, "[compat_test.go:"+line+"]", fmt.Sprintf("%v", stack))
+	assert.Equal(test, "\n"+err.Function()+"\n\t"+err.File()+":"+line, fmt.Sprintf("%+v", stack))
+}
+
+func TestCompatFormatError(test *testing.T) {
+	wrapped := errors.New("wrapped")
+	err := &myError{RuntimeError: *rterror.New("error {p0}", 1).Wrap(wrapped)}
+
+	var f xerrorsFormatter = err
+
+	p := &bufferPrinter{}
+
+	assert.Equal(test, wrapped, f.FormatError(p))
+	assert.Equal(test, "error 1", p.String())
+
+	p = &bufferPrinter{detail: true}
+
+	assert.Equal(test, wrapped, f.FormatError(p))
+	assert.Equal(test, fmt.Sprintf("error 1\n%s\n\t%s:%d", err.Function(), err.File(), err.Line()), p.String())
+}
+
+func TestCompatFormat(test *testing.T) {
+	err := rterror.New("error").SetFormat("{.String}").Wrap(rterror.New("wrapped").SetFormat("{.String}").
+		Wrap(errors.New("root")))
+
+	assert.Equal(test, err.Error(), fmt.Sprintf("%v", err))
+	assert.Equal(test, err.Error(), fmt.Sprintf("%s", err))
+	assert.Equal(test, fmt.Sprintf("%q", err.Error()), fmt.Sprintf("%q", err))
+	assert.Equal(test, "%!d(string="+err.Error()+")", fmt.Sprintf("%d", err))
+	assert.Equal(test, fmt.Sprintf("%x", err.Error()), fmt.Sprintf("%x", err))
+	assert.Equal(test, fmt.Sprintf("% X", err.Error()), fmt.Sprintf("% X", err))
+	assert.Equal(test, fmt.Sprintf("%-40s|", err.Error()), fmt.Sprintf("%-40s|", err))
+	assert.Equal(test, fmt.Sprintf("error\n%s\n\t%s:%d\nwrapped\n%[1]s\n\t%[2]s:%[4]d\nroot",
+		err.Function(), err.File(), err.Line(), err.Line()), fmt.Sprintf("%+v", err))
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// These constants define names of fields set by default context extractors.
+const (
+	FieldDeadlineRemaining = "deadline_remaining"
+	FieldContextCause      = "context_cause"
+)
+
+// ContextExtractor defines a function that enriches runtime error with
+// request-scoped values from context.
+type ContextExtractor func(ctx context.Context, r *RuntimeError)
+
+type contextFieldsKey struct{}
+
+var gContextExtractors atomic.Value // nolint: gochecknoglobals
+
+// NewCtx creates a new runtime error object like the New() function and
+// enriches it with request-scoped values from context using context extractors.
+func NewCtx(ctx context.Context, message string, arguments ...interface{}) *RuntimeError {
+	return NewCtxSkipCaller(ctx, SkipCall, message, arguments...)
+}
+
+// NewCtxSkipCaller creates a new runtime error object like the NewSkipCaller() function
+// and enriches it with request-scoped values from context using context extractors.
+func NewCtxSkipCaller(ctx context.Context, skip int, message string, arguments ...interface{}) *RuntimeError {
+	return NewSkipCaller(skip+SkipCall, message, arguments...).SetContext(ctx)
+}
+
+// FromContext returns nil if context is nil or it is not done. Otherwise, it
+// creates a new runtime error object enriched with request-scoped values from
+// context that wraps the context.Cause() error. Wrapped cause is not duplicated
+// in the FieldContextCause field. It returns the error interface, so returned
+// nil is never a non-nil error interface holding nil runtime error pointer.
+func FromContext(ctx context.Context) error {
+	if (ctx == nil) || (ctx.Err() == nil) {
+		return nil
+	}
+
+	r := NewSkipCaller(SkipCall, "Context is done").SetContext(ctx)
+
+	delete(r.fields, FieldContextCause)
+
+	return r.Wrap(context.Cause(ctx))
+}
+
+// WithErrorFields returns a copy of context with provided runtime error fields.
+// Fields are merged with fields already attached to context. They are set
+// for every runtime error created with the NewCtx() or FromContext() functions.
+func WithErrorFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	parent := ErrorFields(ctx)
+	merged := make(map[string]interface{}, len(parent)+len(fields))
+
+	for key, value := range parent {
+		merged[key] = value
+	}
+
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ErrorFields returns runtime error fields attached to context.
+func ErrorFields(ctx context.Context) map[string]interface{} {
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// ContextKey returns context extractor that sets runtime error field with
+// provided name to the context value for key. Nil values are not set.
+func ContextKey(name string, key interface{}) ContextExtractor {
+	return func(ctx context.Context, r *RuntimeError) {
+		if value := ctx.Value(key); value != nil {
+			r.SetField(name, value)
+		}
+	}
+}
+
+// SetContextExtractors sets package-wide context extractors.
+func SetContextExtractors(extractors ...ContextExtractor) {
+	gContextExtractors.Store(append([]ContextExtractor{}, extractors...))
+}
+
+// AddContextExtractors adds package-wide context extractors.
+func AddContextExtractors(extractors ...ContextExtractor) {
+	SetContextExtractors(append(GetContextExtractors(), extractors...)...)
+}
+
+// GetContextExtractors returns package-wide context extractors.
+func GetContextExtractors() []ContextExtractor {
+	if extractors, ok := gContextExtractors.Load().([]ContextExtractor); ok {
+		return append([]ContextExtractor{}, extractors...)
+	}
+
+	return DefaultContextExtractors()
+}
+
+// ResetContextExtractors resets package-wide context extractors to default values.
+func ResetContextExtractors() {
+	SetContextExtractors(DefaultContextExtractors()...)
+}
+
+// DefaultContextExtractors returns default context extractors. They set fields
+// attached with the WithErrorFields() function, remaining time to context deadline
+// and context cause when context is done.
+func DefaultContextExtractors() []ContextExtractor {
+	return []ContextExtractor{
+		extractErrorFields,
+		extractDeadline,
+		extractCause,
+	}
+}
+
+// SetContext enriches runtime error with request-scoped values from context
+// using package-wide context extractors.
+func (r *RuntimeError) SetContext(ctx context.Context) *RuntimeError {
+	if ctx == nil {
+		return r
+	}
+
+	extractors, ok := gContextExtractors.Load().([]ContextExtractor)
+
+	if !ok {
+		extractors = DefaultContextExtractors()
+	}
+
+	for _, extractor := range extractors {
+		extractor(ctx, r)
+	}
+
+	return r
+}
+
+func extractErrorFields(ctx context.Context, r *RuntimeError) {
+	r.SetFields(ErrorFields(ctx))
+}
+
+func extractDeadline(ctx context.Context, r *RuntimeError) {
+	if deadline, ok := ctx.Deadline(); ok {
+		r.SetField(FieldDeadlineRemaining, deadline.Sub(GetClock()()))
+	}
+}
+
+func extractCause(ctx context.Context, r *RuntimeError) {
+	if ctx.Err() != nil {
+		r.SetField(FieldContextCause, context.Cause(ctx))
+	}
+}
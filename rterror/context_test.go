@@ -0,0 +1,99 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+type requestIDKey struct{}
+
+func TestNewCtx(test *testing.T) {
+	ctx := rterror.WithErrorFields(context.Background(), map[string]interface{}{"user": "foo"})
+	ctx = rterror.WithErrorFields(ctx, map[string]interface{}{"request": 5})
+
+	err := rterror.NewCtx(ctx, "error {p0}", 3)
+
+	assert.Equal(test, "gitlab.com/tymonx/go-error/rterror_test.TestNewCtx", err.Function())
+	assert.Equal(test, "error 3", err.String())
+	assert.Equal(test, map[string]interface{}{"user": "foo", "request": 5}, err.GetFields())
+}
+
+func TestNewCtxDeadline(test *testing.T) {
+	setCapture(test)
+
+	ctx, cancel := context.WithDeadline(context.Background(), testTime.Add(time.Second))
+	defer cancel()
+
+	assert.Equal(test, time.Second, rterror.NewCtx(ctx, "error").GetField(rterror.FieldDeadlineRemaining))
+}
+
+func TestNewCtxCause(test *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(syscall.ECONNRESET)
+
+	assert.Equal(test, syscall.ECONNRESET, rterror.NewCtx(ctx, "error").GetField(rterror.FieldContextCause))
+}
+
+func TestFromContext(test *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	err := rterror.FromContext(ctx)
+
+	assert.True(test, err == nil)
+
+	cancel(syscall.ECONNRESET)
+	err = rterror.FromContext(ctx)
+
+	var r *rterror.RuntimeError
+
+	assert.True(test, errors.As(err, &r))
+	assert.Equal(test, "gitlab.com/tymonx/go-error/rterror_test.TestFromContext", r.Function())
+	assert.True(test, errors.Is(err, syscall.ECONNRESET))
+	assert.Nil(test, r.GetField(rterror.FieldContextCause))
+
+	var empty context.Context
+
+	assert.True(test, rterror.FromContext(empty) == nil)
+}
+
+func TestContextKey(test *testing.T) {
+	defer rterror.ResetContextExtractors()
+
+	rterror.AddContextExtractors(rterror.ContextKey("request_id", requestIDKey{}))
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+
+	assert.Len(test, rterror.GetContextExtractors(), 4)
+	assert.Equal(test, "abc", rterror.NewCtx(ctx, "error").GetField("request_id"))
+	assert.Nil(test, rterror.NewCtx(context.Background(), "error").GetField("request_id"))
+}
+
+func TestSetContextExtractors(test *testing.T) {
+	defer rterror.ResetContextExtractors()
+
+	rterror.SetContextExtractors()
+
+	ctx := rterror.WithErrorFields(context.Background(), map[string]interface{}{"user": "foo"})
+
+	assert.Empty(test, rterror.NewCtx(ctx, "error").GetFields())
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package debugz implements bounded in-memory ring buffer of recently reported
+// errors with HTTP handler that lists them in HTML or JSON format:
+//
+//  recorder := debugz.New(debugz.DefaultSize)
+//
+//  http.Handle("/debug/errors", recorder)
+//
+//  recorder.Record(err)
+//
+// Errors are recorded explicitly when they are reported, after their kind,
+// fields and wrapped errors were set. The recorder stores a snapshot of
+// recorded error, it never accesses recorded error again.
+//
+// The list can be filtered by the kind, package and fingerprint query parameters.
+// The JSON format is selected with the format=json query parameter or with
+// the Accept: application/json request header.
+package debugz
@@ -0,0 +1,145 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugz
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// Filter defines a filter of recorded runtime errors. Empty filter values match all.
+type Filter struct {
+	Kind        string
+	Package     string
+	Fingerprint string
+}
+
+type item struct {
+	Time        time.Time       `json:"time"`
+	Kind        rterror.Kind    `json:"kind,omitempty"`
+	Package     string          `json:"package"`
+	Function    string          `json:"function"`
+	Fingerprint string          `json:"fingerprint"`
+	Message     string          `json:"message"`
+	Detail      json.RawMessage `json:"detail,omitempty"`
+}
+
+var gTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Recent errors</title>
+<style>
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px; text-align: left; vertical-align: top; }
+pre { margin: 0; }
+</style>
+</head>
+<body>
+<h1>Recent errors</h1>
+<table>
+<tr><th>Time</th><th>Kind</th><th>Package</th><th>Function</th><th>Fingerprint</th><th>Error</th></tr>
+{{- range .}}
+<tr>
+<td>{{.Time.Format "2006-01-02T15:04:05.000000Z07:00"}}</td>
+<td><a href="?kind={{.Kind}}">{{.Kind}}</a></td>
+<td><a href="?package={{.Package}}">{{.Package}}</a></td>
+<td>{{.Function}}</td>
+<td><a href="?fingerprint={{.Fingerprint}}">{{.Fingerprint}}</a></td>
+<td><pre>{{.Message}}</pre></td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`)) // nolint: gochecknoglobals
+
+// NewFilter creates a new filter object from the kind, package and fingerprint URL query parameters.
+func NewFilter(query url.Values) *Filter {
+	return &Filter{
+		Kind:        query.Get("kind"),
+		Package:     query.Get("package"),
+		Fingerprint: query.Get("fingerprint"),
+	}
+}
+
+// Match returns true if recorded entry matches filter. Otherwise, it returns false.
+func (f *Filter) Match(entry *Entry) bool {
+	return ((f.Kind == "") || (f.Kind == string(entry.Kind))) &&
+		((f.Package == "") || (f.Package == entry.Package)) &&
+		((f.Fingerprint == "") || (f.Fingerprint == entry.Fingerprint))
+}
+
+// Filter returns stored entries matching provided filter from the newest to the oldest.
+func (r *Recorder) Filter(filter *Filter) []*Entry {
+	entries := r.Entries()
+	filtered := entries[:0]
+
+	for _, entry := range entries {
+		if filter.Match(entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+
+	return filtered
+}
+
+// ServeHTTP lists recorded errors in HTML or JSON format.
+func (r *Recorder) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	entries := r.Filter(NewFilter(query))
+	items := make([]*item, 0, len(entries))
+
+	for _, entry := range entries {
+		items = append(items, &item{
+			Time:        entry.Time,
+			Kind:        entry.Kind,
+			Package:     entry.Package,
+			Function:    entry.Function,
+			Fingerprint: entry.Fingerprint,
+			Message:     entry.Message,
+			Detail:      entry.Detail,
+		})
+	}
+
+	if isJSON(request) {
+		writer.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(writer).Encode(items); err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := gTemplate.Execute(writer, items); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func isJSON(request *http.Request) bool {
+	if format := request.URL.Query().Get("format"); format != "" {
+		return format == "json"
+	}
+
+	return strings.Contains(request.Header.Get("Accept"), "application/json")
+}
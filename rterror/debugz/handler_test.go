@@ -0,0 +1,104 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugz_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/debugz"
+)
+
+type item struct {
+	Kind        string `json:"kind"`
+	Package     string `json:"package"`
+	Function    string `json:"function"`
+	Fingerprint string `json:"fingerprint"`
+	Message     string `json:"message"`
+}
+
+func newRecorder() (recorder *debugz.Recorder, a, b *rterror.RuntimeError) {
+	recorder = debugz.New(debugz.DefaultSize)
+
+	a = rterror.New("error A").SetKind("internal").SetFormat("{.String}")
+	b = rterror.New("<error B>").SetKind("not_found").SetFormat("{.String}")
+
+	recorder.Record(a)
+	recorder.Record(b)
+
+	return recorder, a, b
+}
+
+func serve(recorder *debugz.Recorder, target string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+
+	recorder.ServeHTTP(response, httptest.NewRequest("GET", target, nil))
+
+	return response
+}
+
+func TestHandlerJSON(test *testing.T) {
+	recorder, a, _ := newRecorder()
+
+	response := serve(recorder, "/debug/errors?format=json&kind=internal")
+
+	var items []item
+
+	assert.Equal(test, "application/json", response.Header().Get("Content-Type"))
+	assert.NoError(test, json.Unmarshal(response.Body.Bytes(), &items))
+	assert.Equal(test, []item{{
+		Kind:        "internal",
+		Package:     "gitlab.com/tymonx/go-error/rterror/debugz_test",
+		Function:    "gitlab.com/tymonx/go-error/rterror/debugz_test.newRecorder",
+		Fingerprint: a.Fingerprint(),
+		Message:     "error A",
+	}}, items)
+}
+
+func TestHandlerAcceptJSON(test *testing.T) {
+	recorder, _, _ := newRecorder()
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/debug/errors", nil)
+	request.Header.Set("Accept", "application/json")
+
+	recorder.ServeHTTP(response, request)
+
+	var items []item
+
+	assert.NoError(test, json.Unmarshal(response.Body.Bytes(), &items))
+	assert.Len(test, items, 2)
+}
+
+func TestHandlerHTML(test *testing.T) {
+	recorder, _, b := newRecorder()
+
+	response := serve(recorder, "/debug/errors?fingerprint="+b.Fingerprint())
+	body := response.Body.String()
+
+	assert.Equal(test, "text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Contains(test, body, "&lt;error B&gt;")
+	assert.NotContains(test, body, "error A")
+}
+
+func TestFilterPackage(test *testing.T) {
+	recorder, _, _ := newRecorder()
+
+	assert.Len(test, recorder.Filter(&debugz.Filter{Package: "gitlab.com/tymonx/go-error/rterror/debugz_test"}), 2)
+	assert.Empty(test, recorder.Filter(&debugz.Filter{Package: "unknown"}))
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugz
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// DefaultSize defines default number of stored runtime errors.
+const DefaultSize = 100
+
+// Entry defines a snapshot of recorded error. It is taken when error is
+// recorded, later modifications of recorded error are not visible.
+type Entry struct {
+	Sequence    uint64
+	Time        time.Time
+	Kind        rterror.Kind
+	Package     string
+	Function    string
+	Fingerprint string
+	Message     string
+	Detail      json.RawMessage
+}
+
+// Recorder defines a bounded ring buffer of recently recorded errors.
+// Recording is lock-free, the oldest entries are overwritten by the newest.
+type Recorder struct {
+	next  uint64
+	slots []atomic.Value
+}
+
+// New creates a new recorder object that stores up to size recent runtime errors.
+// Size lower than 1 is treated as DefaultSize.
+func New(size int) *Recorder {
+	if size < 1 {
+		size = DefaultSize
+	}
+
+	return &Recorder{
+		slots: make([]atomic.Value, size),
+	}
+}
+
+// Size returns maximum number of stored runtime errors.
+func (r *Recorder) Size() int {
+	return len(r.slots)
+}
+
+// Record stores a snapshot of provided error. It should be called when error
+// is reported, after its kind, fields and wrapped errors were set. Recording
+// nil error does nothing.
+func (r *Recorder) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	entry := &Entry{
+		Time:    time.Now(),
+		Message: err.Error(),
+	}
+
+	var e *rterror.RuntimeError
+
+	if errors.As(err, &e) && (e != nil) {
+		entry.Kind = e.GetKind()
+		entry.Package = e.Package()
+		entry.Function = e.Function()
+		entry.Fingerprint = e.Fingerprint()
+
+		if detail, jsonErr := json.Marshal(e); jsonErr == nil {
+			entry.Detail = detail
+		}
+	}
+
+	entry.Sequence = atomic.AddUint64(&r.next, 1) - 1
+
+	r.slots[entry.Sequence%uint64(len(r.slots))].Store(entry)
+}
+
+// Entries returns stored entries from the newest to the oldest.
+func (r *Recorder) Entries() []*Entry {
+	next := atomic.LoadUint64(&r.next)
+	size := uint64(len(r.slots))
+	entries := make([]*Entry, 0, len(r.slots))
+
+	for sequence := next; (sequence > 0) && ((next - sequence) < size); sequence-- {
+		entry, ok := r.slots[(sequence-1)%size].Load().(*Entry)
+
+		// Skip not yet stored or already overwritten entries
+		if ok && (entry != nil) && (entry.Sequence == (sequence - 1)) {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Reset removes all stored entries.
+func (r *Recorder) Reset() *Recorder {
+	for index := range r.slots {
+		r.slots[index].Store((*Entry)(nil))
+	}
+
+	return r
+}
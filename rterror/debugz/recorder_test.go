@@ -0,0 +1,144 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugz_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/debugz"
+)
+
+func TestRecorder(test *testing.T) {
+	recorder := debugz.New(2)
+
+	recorder.Record(rterror.New("A").SetFormat("{.String}"))
+	recorder.Record(rterror.New("B").SetFormat("{.String}").SetKind("not_found"))
+	recorder.Record(rterror.New("C").SetFormat("{.String}"))
+	recorder.Record(nil)
+
+	entries := recorder.Entries()
+
+	assert.Equal(test, 2, recorder.Size())
+	assert.Len(test, entries, 2)
+	assert.Equal(test, "C", entries[0].Message)
+	assert.Equal(test, "B", entries[1].Message)
+	assert.Equal(test, rterror.Kind("not_found"), entries[1].Kind)
+	assert.False(test, entries[0].Time.Before(entries[1].Time))
+}
+
+func TestRecorderWrapped(test *testing.T) {
+	recorder := debugz.New(2)
+	err := rterror.New("inner").SetFormat("{.String}").SetKind("internal")
+
+	recorder.Record(fmt.Errorf("outer: %w", err))
+	recorder.Record(errors.New("plain"))
+
+	entries := recorder.Entries()
+
+	assert.Len(test, entries, 2)
+	assert.Equal(test, "plain", entries[0].Message)
+	assert.Empty(test, entries[0].Fingerprint)
+	assert.Equal(test, "outer: inner", entries[1].Message)
+	assert.Equal(test, rterror.Kind("internal"), entries[1].Kind)
+	assert.Equal(test, err.Fingerprint(), entries[1].Fingerprint)
+}
+
+func TestRecorderSnapshot(test *testing.T) {
+	recorder := debugz.New(2)
+	err := rterror.New("error").SetFormat("{.String}").SetKind("internal")
+
+	recorder.Record(err)
+
+	err.SetKind("changed").Wrap(errors.New("cause"))
+
+	entries := recorder.Entries()
+
+	assert.Len(test, entries, 1)
+	assert.Equal(test, rterror.Kind("internal"), entries[0].Kind)
+	assert.Equal(test, "error", entries[0].Message)
+}
+
+func TestRecorderDefaultSize(test *testing.T) {
+	assert.Equal(test, debugz.DefaultSize, debugz.New(0).Size())
+}
+
+func TestRecorderReset(test *testing.T) {
+	recorder := debugz.New(2)
+
+	recorder.Record(rterror.New("error"))
+	recorder.Reset()
+
+	assert.Empty(test, recorder.Entries())
+}
+
+func TestRecorderConcurrent(test *testing.T) {
+	recorder := debugz.New(16)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				recorder.Record(rterror.New("error"))
+				recorder.Entries()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Len(test, recorder.Entries(), 16)
+}
+
+func TestRecorderServeWhileModified(test *testing.T) {
+	recorder := debugz.New(4)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				err := rterror.New("error").SetKind("internal")
+
+				recorder.Record(err)
+
+				err.SetKind("changed").SetField("key", j).Wrap(errors.New("cause"))
+			}
+		}()
+	}
+
+	for j := 0; j < 100; j++ {
+		serve(recorder, "/debug/errors?format=json")
+	}
+
+	wg.Wait()
+
+	for _, entry := range recorder.Entries() {
+		assert.Equal(test, rterror.Kind("internal"), entry.Kind)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// These constants define stable placeholders used in deterministic mode.
+const (
+	DeterministicLine = 0
+	DeterministicPath = "<path>"
+	DeterministicID   = "<id>"
+)
+
+// Cleaner defines an interface that registers cleanup function. It is
+// implemented by the testing.T, testing.B and testing.F types.
+type Cleaner interface {
+	Helper()
+	Cleanup(cleanup func())
+}
+
+// locator defines runtime error accessors replaced in deterministic mode.
+type locator interface {
+	Line() int
+	File() string
+	Time() time.Time
+	ID() string
+}
+
+// deterministicError overrides runtime error accessors with stable placeholders.
+// It is used only when runtime error is rendered or serialized, the Line(),
+// File(), Time() and ID() methods of runtime error always return real values.
+type deterministicError struct {
+	*RuntimeError
+}
+
+var gDeterministic int32 // nolint: gochecknoglobals
+
+// SetDeterministic enables package-wide deterministic mode until the end of
+// test. Previous mode is restored by the t.Cleanup() method. In deterministic
+// mode, line numbers, absolute file paths, creation times and identifiers are
+// replaced with stable placeholders and package and function names are kept.
+// It applies only to the Error() and TopError() methods, the %+v verb and the
+// MarshalJSON() method. The Line(), File(), Time() and ID() methods are not
+// affected, so tools like profilers still get real values.
+// Deterministic mode is package-wide, it must not be used in parallel tests.
+func SetDeterministic(t Cleaner) {
+	t.Helper()
+
+	previous := atomic.SwapInt32(&gDeterministic, boolToInt32(true))
+
+	t.Cleanup(func() {
+		atomic.StoreInt32(&gDeterministic, previous)
+	})
+}
+
+// EnableDeterministic enables package-wide deterministic mode.
+func EnableDeterministic() {
+	atomic.StoreInt32(&gDeterministic, boolToInt32(true))
+}
+
+// DisableDeterministic disables package-wide deterministic mode.
+func DisableDeterministic() {
+	atomic.StoreInt32(&gDeterministic, boolToInt32(false))
+}
+
+// IsDeterministicEnabled returns true if deterministic mode is enabled. Otherwise, it returns false.
+func IsDeterministicEnabled() bool {
+	return atomic.LoadInt32(&gDeterministic) != 0
+}
+
+// deterministicTime returns stable placeholder for non-zero creation time.
+func deterministicTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	return time.Unix(0, 0).UTC()
+}
+
+// deterministicID returns stable placeholder for non-empty identifier.
+func deterministicID(id string) string {
+	if id == "" {
+		return id
+	}
+
+	return DeterministicID
+}
+
+// locator returns runtime error or its deterministic view in deterministic mode.
+func (r *RuntimeError) locator() locator {
+	if IsDeterministicEnabled() {
+		return &deterministicError{
+			RuntimeError: r,
+		}
+	}
+
+	return r
+}
+
+// Line returns DeterministicLine.
+func (d *deterministicError) Line() int {
+	return DeterministicLine
+}
+
+// File returns file path with directory replaced by DeterministicPath.
+func (d *deterministicError) File() string {
+	return DeterministicPath + "/" + filepath.Base(d.RuntimeError.File())
+}
+
+// Time returns the Unix epoch for non-zero creation time.
+func (d *deterministicError) Time() time.Time {
+	return deterministicTime(d.RuntimeError.Time())
+}
+
+// ID returns DeterministicID for non-empty identifier.
+func (d *deterministicError) ID() string {
+	return deterministicID(d.RuntimeError.ID())
+}
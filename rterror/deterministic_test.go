@@ -0,0 +1,71 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func TestDeterministic(test *testing.T) {
+	test.Run("enabled", func(t *testing.T) {
+		setCapture(t)
+		rterror.SetDeterministic(t)
+
+		err := rterror.New("error {p0}", 3).SetFormat("{.Package}:{.File}:{.Line}:{.FunctionBase}(): {.String} {.ID}").
+			Wrap(rterror.New("wrapped").SetFormat("{.FileBase}:{.Line}: {.String} {.Time.Unix}"))
+
+		data, e := err.MarshalJSON()
+
+		assert.True(t, rterror.IsDeterministicEnabled())
+		assert.NotEqual(t, rterror.DeterministicLine, err.Line())
+		assert.NotContains(t, err.File(), rterror.DeterministicPath)
+		assert.NotEqual(t, rterror.DeterministicID, err.ID())
+		assert.False(t, err.Time().Equal(time.Unix(0, 0)))
+		assert.Equal(t, "gitlab.com/tymonx/go-error/rterror_test:<path>/deterministic_test.go:0:"+
+			"TestDeterministic.func1(): error 3 <id>\n`--deterministic_test.go:0: wrapped 0", err.Error())
+		assert.Equal(t, "error 3\ngitlab.com/tymonx/go-error/rterror_test.TestDeterministic.func1\n\t<path>/deterministic_test.go:0\n"+
+			"wrapped\ngitlab.com/tymonx/go-error/rterror_test.TestDeterministic.func1\n\t<path>/deterministic_test.go:0", fmt.Sprintf("%+v", err))
+		assert.NoError(t, e)
+		assert.JSONEq(t, `{
+			"line": 0,
+			"file": "<path>/deterministic_test.go",
+			"function": "gitlab.com/tymonx/go-error/rterror_test.TestDeterministic.func1",
+			"message": "error {p0}",
+			"arguments": [3],
+			"time": "1970-01-01T00:00:00Z",
+			"id": "<id>"
+		}`, string(data))
+	})
+
+	err := rterror.New("error")
+
+	assert.False(test, rterror.IsDeterministicEnabled())
+	assert.NotEqual(test, rterror.DeterministicLine, err.Line())
+	assert.NotContains(test, err.File(), rterror.DeterministicPath)
+}
+
+func TestDeterministicUncaptured(test *testing.T) {
+	rterror.SetDeterministic(test)
+
+	err := rterror.New("error")
+
+	assert.True(test, err.Time().IsZero())
+	assert.Empty(test, err.ID())
+}
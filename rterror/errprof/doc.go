@@ -0,0 +1,25 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errprof implements error profile that shows which stacks create
+// runtime errors. Stacks are sampled using runtime error creation hooks and
+// the profile is written in the gzipped pprof format that can be viewed with
+// the go tool pprof command:
+//
+//  profiler := errprof.New().Register()
+//
+//  http.Handle("/debug/pprof/errors", profiler)
+//
+//  go tool pprof http://localhost:8080/debug/pprof/errors
+package errprof
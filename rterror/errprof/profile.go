@@ -0,0 +1,211 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errprof
+
+import (
+	"runtime"
+	"time"
+)
+
+// Field numbers of the profile.proto messages.
+const (
+	tagProfileSampleType        = 1
+	tagProfileSample            = 2
+	tagProfileLocation          = 4
+	tagProfileFunction          = 5
+	tagProfileStringTable       = 6
+	tagProfileTimeNanos         = 9
+	tagProfileDurationNanos     = 10
+	tagProfilePeriodType        = 11
+	tagProfilePeriod            = 12
+	tagProfileDefaultSampleType = 14
+
+	tagValueTypeType = 1
+	tagValueTypeUnit = 2
+
+	tagSampleLocationID = 1
+	tagSampleValue      = 2
+
+	tagLocationID      = 1
+	tagLocationAddress = 3
+	tagLocationLine    = 4
+
+	tagLineFunctionID = 1
+	tagLineLine       = 2
+
+	tagFunctionID         = 1
+	tagFunctionName       = 2
+	tagFunctionSystemName = 3
+	tagFunctionFilename   = 4
+	tagFunctionStartLine  = 5
+)
+
+type function struct {
+	name string
+	file string
+}
+
+// builder builds profile message in the profile.proto format.
+type builder struct {
+	start     time.Time
+	rate      int64
+	samples   protobuf
+	locations protobuf
+	functions protobuf
+	strings   []string
+	stringIDs map[string]int64
+	pcIDs     map[uintptr]uint64
+	funcIDs   map[function]uint64
+}
+
+func newBuilder(start time.Time, rate int64) *builder {
+	return &builder{
+		start:     start,
+		rate:      rate,
+		strings:   []string{""},
+		stringIDs: map[string]int64{"": 0},
+		pcIDs:     make(map[uintptr]uint64),
+		funcIDs:   make(map[function]uint64),
+	}
+}
+
+func (b *builder) add(stack []uintptr, count int64) {
+	locations := make([]uint64, 0, len(stack))
+
+	for _, pc := range stack {
+		locations = append(locations, b.location(pc))
+	}
+
+	b.samples.message(tagProfileSample, func(m *protobuf) {
+		m.uint64s(tagSampleLocationID, locations)
+		m.int64s(tagSampleValue, []int64{count, count * b.rate})
+	})
+}
+
+func (b *builder) build() []byte {
+	var profile protobuf
+
+	samples, errors := b.string("samples"), b.string("errors")
+	count := b.string("count")
+
+	profile.message(tagProfileSampleType, valueType(samples, count))
+	profile.message(tagProfileSampleType, valueType(errors, count))
+	profile.bytes(b.samples.data)
+	profile.bytes(b.locations.data)
+	profile.bytes(b.functions.data)
+
+	for _, s := range b.strings {
+		profile.string(tagProfileStringTable, s)
+	}
+
+	now := time.Now()
+
+	profile.int64(tagProfileTimeNanos, b.start.UnixNano())
+	profile.int64(tagProfileDurationNanos, now.Sub(b.start).Nanoseconds())
+	profile.message(tagProfilePeriodType, valueType(errors, count))
+	profile.int64(tagProfilePeriod, b.rate)
+	profile.int64(tagProfileDefaultSampleType, errors)
+
+	return profile.data
+}
+
+func (b *builder) location(pc uintptr) uint64 {
+	if id, ok := b.pcIDs[pc]; ok {
+		return id
+	}
+
+	id := uint64(len(b.pcIDs) + 1)
+	b.pcIDs[pc] = id
+
+	type line struct {
+		function uint64
+		line     int64
+	}
+
+	var lines []line
+
+	frames := runtime.CallersFrames([]uintptr{pc})
+
+	for {
+		frame, more := frames.Next()
+
+		lines = append(lines, line{
+			function: b.function(frame.Function, frame.File),
+			line:     int64(frame.Line),
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	b.locations.message(tagProfileLocation, func(m *protobuf) {
+		m.uint64(tagLocationID, id)
+		m.uint64(tagLocationAddress, uint64(pc))
+
+		for _, l := range lines {
+			m.message(tagLocationLine, func(m *protobuf) {
+				m.uint64(tagLineFunctionID, l.function)
+				m.int64(tagLineLine, l.line)
+			})
+		}
+	})
+
+	return id
+}
+
+func (b *builder) function(name, file string) uint64 {
+	key := function{
+		name: name,
+		file: file,
+	}
+
+	if id, ok := b.funcIDs[key]; ok {
+		return id
+	}
+
+	id := uint64(len(b.funcIDs) + 1)
+	b.funcIDs[key] = id
+
+	b.functions.message(tagProfileFunction, func(m *protobuf) {
+		m.uint64(tagFunctionID, id)
+		m.int64(tagFunctionName, b.string(name))
+		m.int64(tagFunctionSystemName, b.string(name))
+		m.int64(tagFunctionFilename, b.string(file))
+		m.int64(tagFunctionStartLine, 0)
+	})
+
+	return id
+}
+
+func (b *builder) string(s string) int64 {
+	if id, ok := b.stringIDs[s]; ok {
+		return id
+	}
+
+	id := int64(len(b.strings))
+
+	b.strings = append(b.strings, s)
+	b.stringIDs[s] = id
+
+	return id
+}
+
+func valueType(_type, unit int64) func(m *protobuf) {
+	return func(m *protobuf) {
+		m.int64(tagValueTypeType, _type)
+		m.int64(tagValueTypeUnit, unit)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errprof
+
+import (
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// These constants define default values used by profiler.
+const (
+	DefaultRate  = 1
+	MaxStackSize = 64
+	ContentType  = "application/octet-stream"
+)
+
+// Profiler defines an error profile. It samples stacks from where runtime
+// errors were created. With the rate N only every N-th runtime error is sampled.
+type Profiler struct {
+	counter uint64
+	rate    int64
+	mutex   sync.Mutex
+	remove  func()
+	start   time.Time
+	samples map[string]*sample
+}
+
+type sample struct {
+	stack []uintptr
+	count int64
+}
+
+// New creates a new profiler object with default sampling rate.
+func New() *Profiler {
+	return &Profiler{
+		rate:    DefaultRate,
+		start:   time.Now(),
+		samples: make(map[string]*sample),
+	}
+}
+
+// SetRate sets sampling rate. Only every N-th created runtime error is sampled.
+// Rate lower than 1 is treated as 1, all runtime errors are sampled.
+func (p *Profiler) SetRate(rate int) *Profiler {
+	if rate < 1 {
+		rate = 1
+	}
+
+	atomic.StoreInt64(&p.rate, int64(rate))
+
+	return p
+}
+
+// GetRate returns sampling rate.
+func (p *Profiler) GetRate() int {
+	return int(atomic.LoadInt64(&p.rate))
+}
+
+// Register registers profiler with the runtime error creation hooks.
+// Registering already registered profiler does nothing.
+func (p *Profiler) Register() *Profiler {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.remove == nil {
+		p.remove = rterror.OnCreate(p.Observe)
+	}
+
+	return p
+}
+
+// Unregister unregisters profiler from the runtime error creation hooks.
+func (p *Profiler) Unregister() *Profiler {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.remove != nil {
+		p.remove()
+		p.remove = nil
+	}
+
+	return p
+}
+
+// Observe samples stack from where provided runtime error was created.
+// It must be called from the same goroutine that created runtime error.
+func (p *Profiler) Observe(e *rterror.RuntimeError) {
+	if (atomic.AddUint64(&p.counter, 1) % uint64(p.GetRate())) != 0 {
+		return
+	}
+
+	var pcs [MaxStackSize]uintptr
+
+	stack := trim(e, pcs[:runtime.Callers(1, pcs[:])])
+	key := stackKey(stack)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	s, ok := p.samples[key]
+
+	if !ok {
+		s = &sample{
+			stack: append([]uintptr(nil), stack...),
+		}
+
+		p.samples[key] = s
+	}
+
+	s.count++
+}
+
+// Reset removes all collected samples.
+func (p *Profiler) Reset() *Profiler {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.start = time.Now()
+	p.samples = make(map[string]*sample)
+
+	return p
+}
+
+// WriteProfile writes gzipped error profile in the pprof format to writer.
+func (p *Profiler) WriteProfile(writer io.Writer) error {
+	p.mutex.Lock()
+
+	b := newBuilder(p.start, int64(p.GetRate()))
+
+	for _, s := range p.samples {
+		b.add(s.stack, s.count)
+	}
+
+	p.mutex.Unlock()
+
+	compressed := gzip.NewWriter(writer)
+
+	if _, err := compressed.Write(b.build()); err != nil {
+		return err
+	}
+
+	return compressed.Close()
+}
+
+// ServeHTTP writes gzipped error profile in the pprof format.
+func (p *Profiler) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", ContentType)
+	writer.Header().Set("Content-Disposition", `attachment; filename="errors"`)
+
+	if err := p.WriteProfile(writer); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// trim removes stack frames above the place from where runtime error was
+// created. These are hooks, profiler and runtime error constructor frames.
+func trim(e *rterror.RuntimeError, stack []uintptr) []uintptr {
+	function, line := e.Function(), e.Line()
+
+	for index, pc := range stack {
+		frames := runtime.CallersFrames([]uintptr{pc})
+
+		for {
+			frame, more := frames.Next()
+
+			if (frame.Function == function) && (frame.Line == line) {
+				return stack[index:]
+			}
+
+			if !more {
+				break
+			}
+		}
+	}
+
+	return stack
+}
+
+func stackKey(stack []uintptr) string {
+	key := make([]byte, 8*len(stack))
+
+	for index, pc := range stack {
+		binary.LittleEndian.PutUint64(key[8*index:], uint64(pc))
+	}
+
+	return string(key)
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errprof_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/errprof"
+)
+
+type profile struct {
+	strings []string
+	samples [][]int64
+}
+
+// decode decodes string table and sample values from the profile.proto message.
+func decode(test *testing.T, data []byte) *profile {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(test, err)
+
+	data, err = ioutil.ReadAll(reader)
+	assert.NoError(test, err)
+
+	p := new(profile)
+
+	fields(data, func(tag int, value []byte) {
+		switch tag {
+		case 2:
+			fields(value, func(tag int, value []byte) {
+				if tag == 2 {
+					var values []int64
+
+					for len(value) != 0 {
+						x, n := binary.Uvarint(value)
+						values, value = append(values, int64(x)), value[n:]
+					}
+
+					p.samples = append(p.samples, values)
+				}
+			})
+		case 6:
+			p.strings = append(p.strings, string(value))
+		}
+	})
+
+	return p
+}
+
+func fields(data []byte, callback func(tag int, value []byte)) {
+	for len(data) != 0 {
+		key, n := binary.Uvarint(data)
+		data = data[n:]
+
+		if key&7 == 0 {
+			_, n = binary.Uvarint(data)
+			data = data[n:]
+
+			continue
+		}
+
+		length, n := binary.Uvarint(data)
+		callback(int(key>>3), data[n:n+int(length)])
+		data = data[n+int(length):]
+	}
+}
+
+func createErrors(count int) {
+	for i := 0; i < count; i++ {
+		rterror.New("error")
+	}
+}
+
+func TestProfiler(test *testing.T) {
+	profiler := errprof.New().Register()
+	defer profiler.Unregister()
+
+	createErrors(3)
+
+	var buffer bytes.Buffer
+
+	assert.NoError(test, profiler.WriteProfile(&buffer))
+
+	p := decode(test, buffer.Bytes())
+
+	assert.Equal(test, "", p.strings[0])
+	assert.Contains(test, p.strings, "gitlab.com/tymonx/go-error/rterror/errprof_test.createErrors")
+	assert.Contains(test, p.strings, "gitlab.com/tymonx/go-error/rterror/errprof_test.TestProfiler")
+	assert.NotContains(test, p.strings, "gitlab.com/tymonx/go-error/rterror.New")
+	assert.Equal(test, [][]int64{{3, 3}}, p.samples)
+}
+
+func TestProfilerDeterministic(test *testing.T) {
+	rterror.SetDeterministic(test)
+
+	profiler := errprof.New().Register()
+	defer profiler.Unregister()
+
+	createErrors(1)
+
+	var buffer bytes.Buffer
+
+	assert.NoError(test, profiler.WriteProfile(&buffer))
+	assert.NotContains(test, decode(test, buffer.Bytes()).strings, "gitlab.com/tymonx/go-error/rterror.New")
+}
+
+func TestProfilerRate(test *testing.T) {
+	profiler := errprof.New().SetRate(4).Register()
+	defer profiler.Unregister()
+
+	createErrors(8)
+
+	var buffer bytes.Buffer
+
+	assert.NoError(test, profiler.WriteProfile(&buffer))
+	assert.Equal(test, 4, profiler.GetRate())
+	assert.Equal(test, [][]int64{{2, 8}}, decode(test, buffer.Bytes()).samples)
+}
+
+func TestProfilerReset(test *testing.T) {
+	profiler := errprof.New().Register().Unregister()
+
+	createErrors(1)
+	profiler.Reset()
+
+	var buffer bytes.Buffer
+
+	assert.NoError(test, profiler.WriteProfile(&buffer))
+	assert.Empty(test, decode(test, buffer.Bytes()).samples)
+}
+
+func TestProfilerServeHTTP(test *testing.T) {
+	profiler := errprof.New()
+
+	profiler.Observe(rterror.New("error"))
+
+	recorder := httptest.NewRecorder()
+
+	profiler.ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/pprof/errors", nil))
+
+	assert.Equal(test, errprof.ContentType, recorder.Header().Get("Content-Type"))
+	assert.Len(test, decode(test, recorder.Body.Bytes()).samples, 1)
+}
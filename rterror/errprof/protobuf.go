@@ -0,0 +1,94 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errprof
+
+// Protocol buffers wire types.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// protobuf implements minimal protocol buffers encoder needed by the profile.proto format.
+type protobuf struct {
+	data []byte
+}
+
+func (p *protobuf) varint(x uint64) {
+	for x >= 0x80 {
+		p.data = append(p.data, byte(x)|0x80)
+		x >>= 7
+	}
+
+	p.data = append(p.data, byte(x))
+}
+
+func (p *protobuf) key(tag, wire int) {
+	p.varint(uint64(tag)<<3 | uint64(wire))
+}
+
+func (p *protobuf) uint64(tag int, x uint64) {
+	if x != 0 {
+		p.key(tag, wireVarint)
+		p.varint(x)
+	}
+}
+
+func (p *protobuf) int64(tag int, x int64) {
+	p.uint64(tag, uint64(x))
+}
+
+func (p *protobuf) uint64s(tag int, xs []uint64) {
+	var packed protobuf
+
+	for _, x := range xs {
+		packed.varint(x)
+	}
+
+	p.key(tag, wireBytes)
+	p.varint(uint64(len(packed.data)))
+	p.bytes(packed.data)
+}
+
+func (p *protobuf) int64s(tag int, xs []int64) {
+	var packed protobuf
+
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+
+	p.key(tag, wireBytes)
+	p.varint(uint64(len(packed.data)))
+	p.bytes(packed.data)
+}
+
+func (p *protobuf) string(tag int, s string) {
+	p.key(tag, wireBytes)
+	p.varint(uint64(len(s)))
+	p.data = append(p.data, s...)
+}
+
+func (p *protobuf) message(tag int, encode func(m *protobuf)) {
+	var m protobuf
+
+	encode(&m)
+
+	p.key(tag, wireBytes)
+	p.varint(uint64(len(m.data)))
+	p.bytes(m.data)
+}
+
+func (p *protobuf) bytes(data []byte) {
+	p.data = append(p.data, data...)
+}
@@ -0,0 +1,310 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// VerboseEnv defines environment variable that enables printing of full error
+// details with file paths, line numbers and wrapped errors by the Exit() function.
+const VerboseEnv = "RTERROR_VERBOSE"
+
+// These constants define exit codes. Codes from 64 to 78 follow the sysexits.h conventions.
+const (
+	ExitSuccess     = 0
+	ExitFailure     = 1
+	ExitUsage       = 64
+	ExitDataError   = 65
+	ExitNoInput     = 66
+	ExitNoUser      = 67
+	ExitNoHost      = 68
+	ExitUnavailable = 69
+	ExitSoftware    = 70
+	ExitOSError     = 71
+	ExitOSFile      = 72
+	ExitCantCreate  = 73
+	ExitIOError     = 74
+	ExitTempFail    = 75
+	ExitProtocol    = 76
+	ExitNoPerm      = 77
+	ExitConfig      = 78
+)
+
+// ColorMode defines when error printed by the Exit() function contains ANSI colors.
+type ColorMode int
+
+// These constants define supported color modes.
+const (
+	// ColorAuto keeps ANSI colors only if writer is a terminal and the NO_COLOR
+	// environment variable is not set.
+	ColorAuto ColorMode = iota
+
+	// ColorAlways always keeps ANSI colors.
+	ColorAlways
+
+	// ColorNever always removes ANSI colors.
+	ColorNever
+)
+
+// ExitCoder defines an interface of errors that provide exit code. It is
+// implemented by the exec.ExitError error, so exit code of failed child
+// process is passed through.
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// Exiter defines an object that prints error and exits program with exit code
+// mapped from error. Writer and exit function can be injected for testing.
+type Exiter struct {
+	writer  io.Writer
+	exit    func(code int)
+	codes   map[Kind]int
+	color   ColorMode
+	verbose bool
+}
+
+type exiterHolder struct {
+	exiter *Exiter
+}
+
+var (
+	gExiter     atomic.Value                            // nolint: gochecknoglobals
+	gANSIEscape = regexp.MustCompile("\x1b\\[[0-9;]*m") // nolint: gochecknoglobals
+)
+
+// DefaultExitCodes returns default mapping from runtime error kinds to exit codes.
+func DefaultExitCodes() map[Kind]int {
+	return map[Kind]int{
+		"usage":             ExitUsage,
+		"invalid_argument":  ExitUsage,
+		"invalid_data":      ExitDataError,
+		"not_found":         ExitNoInput,
+		"unavailable":       ExitUnavailable,
+		"internal":          ExitSoftware,
+		"io":                ExitIOError,
+		"timeout":           ExitTempFail,
+		"temporary":         ExitTempFail,
+		"permission_denied": ExitNoPerm,
+		"unauthorized":      ExitNoPerm,
+		"config":            ExitConfig,
+	}
+}
+
+// NewExiter creates a new exiter object that writes to the standard error,
+// exits with the os.Exit() function and maps error kinds using DefaultExitCodes().
+// Verbose mode is taken from the RTERROR_VERBOSE environment variable.
+func NewExiter() *Exiter {
+	return &Exiter{
+		writer:  os.Stderr,
+		exit:    os.Exit,
+		codes:   DefaultExitCodes(),
+		verbose: isEnvEnabled(VerboseEnv),
+	}
+}
+
+// SetExiter sets package-wide exiter used by the Exit() and Main() functions.
+func SetExiter(exiter *Exiter) {
+	gExiter.Store(exiterHolder{
+		exiter: exiter,
+	})
+}
+
+// GetExiter returns package-wide exiter.
+func GetExiter() *Exiter {
+	if holder, ok := gExiter.Load().(exiterHolder); ok && (holder.exiter != nil) {
+		return holder.exiter
+	}
+
+	return NewExiter()
+}
+
+// ResetExiter resets package-wide exiter to default value created by the NewExiter() function.
+func ResetExiter() {
+	SetExiter(nil)
+}
+
+// Exit prints error using package-wide exiter and exits program with exit code
+// mapped from error. It exits with ExitSuccess for nil error.
+func Exit(err error) {
+	GetExiter().Exit(err)
+}
+
+// Main runs provided main function and exits program using package-wide exiter
+// with exit code mapped from returned error. Example:
+//
+//  func main() {
+//      rterror.Main(run)
+//  }
+func Main(main func() error) {
+	GetExiter().Main(main)
+}
+
+// SetWriter sets writer used to print errors.
+func (e *Exiter) SetWriter(writer io.Writer) *Exiter {
+	e.writer = writer
+	return e
+}
+
+// GetWriter returns writer used to print errors.
+func (e *Exiter) GetWriter() io.Writer {
+	return e.writer
+}
+
+// SetExitFunc sets function used to exit program.
+func (e *Exiter) SetExitFunc(exit func(code int)) *Exiter {
+	e.exit = exit
+	return e
+}
+
+// SetExitCode sets exit code for provided runtime error kind.
+func (e *Exiter) SetExitCode(kind Kind, code int) *Exiter {
+	e.codes[kind] = code
+	return e
+}
+
+// SetColor sets color mode.
+func (e *Exiter) SetColor(mode ColorMode) *Exiter {
+	e.color = mode
+	return e
+}
+
+// GetColor returns color mode.
+func (e *Exiter) GetColor() ColorMode {
+	return e.color
+}
+
+// SetVerbose enables or disables verbose mode. In verbose mode, error is
+// printed with the Error() method. Otherwise, only error messages are printed.
+func (e *Exiter) SetVerbose(enabled bool) *Exiter {
+	e.verbose = enabled
+	return e
+}
+
+// IsVerboseEnabled returns true if verbose mode is enabled. Otherwise, it returns false.
+func (e *Exiter) IsVerboseEnabled() bool {
+	return e.verbose
+}
+
+// ExitCode returns exit code mapped from error. It returns ExitSuccess for nil
+// error and exit code of the first ExitCoder error in error chain. Otherwise,
+// it returns exit code for kind of the outermost runtime error with mapped
+// kind or ExitFailure.
+func (e *Exiter) ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
+
+	if coder, ok := Find[ExitCoder](err); ok {
+		if code := coder.ExitCode(); code > 0 {
+			return code
+		}
+
+		return ExitFailure
+	}
+
+	for _, r := range RuntimeErrors(err) {
+		if code, ok := e.codes[r.kind]; ok && (r.kind != "") {
+			return code
+		}
+	}
+
+	return ExitFailure
+}
+
+// Print prints error to writer. In verbose mode, error is printed with the
+// Error() method. Otherwise, error messages of error chain are printed in one line.
+func (e *Exiter) Print(err error) {
+	if err == nil {
+		return
+	}
+
+	var message string
+
+	if e.verbose {
+		message = err.Error()
+	} else {
+		message = Summary(err)
+	}
+
+	if !e.isColorEnabled() {
+		message = gANSIEscape.ReplaceAllString(message, "")
+	}
+
+	_, _ = io.WriteString(e.writer, message+"\n")
+}
+
+// Exit prints error and exits with exit code mapped from error. It exits with
+// ExitSuccess and prints nothing for nil error.
+func (e *Exiter) Exit(err error) {
+	e.Print(err)
+	e.exit(e.ExitCode(err))
+}
+
+// Main runs provided main function and exits with exit code mapped from returned error.
+func (e *Exiter) Main(main func() error) {
+	e.Exit(main())
+}
+
+func (e *Exiter) isColorEnabled() bool {
+	switch e.color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	case ColorAuto:
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	file, ok := e.writer.(*os.File)
+
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+
+	return (err == nil) && ((info.Mode() & os.ModeCharDevice) != 0)
+}
+
+// Summary returns error messages of error chain joined with DefaultSeparator in
+// single line without location. Runtime errors contribute only formatted error
+// messages. Other errors already contain messages of wrapped errors, so walking
+// stops at them. It returns empty string for nil error.
+func Summary(err error) string {
+	messages := []string{}
+
+	for err != nil {
+		r, ok := err.(*RuntimeError)
+
+		if !ok {
+			messages = append(messages, err.Error())
+			break
+		}
+
+		messages = append(messages, r.String())
+		err = errors.Unwrap(r)
+	}
+
+	return strings.Join(messages, DefaultSeparator)
+}
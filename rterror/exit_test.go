@@ -0,0 +1,145 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return "exit code error"
+}
+
+func (e *exitCodeError) ExitCode() int {
+	return e.code
+}
+
+func newTestExiter(writer *bytes.Buffer, code *int) *rterror.Exiter {
+	return rterror.NewExiter().SetWriter(writer).SetVerbose(false).SetExitFunc(func(c int) {
+		*code = c
+	})
+}
+
+func TestExit(test *testing.T) {
+	var writer bytes.Buffer
+
+	code := -1
+	exiter := newTestExiter(&writer, &code)
+
+	exiter.Exit(rterror.New("cannot open {p0}", "config.yaml").SetKind("config").
+		Wrap(fmt.Errorf("read: %w", errors.New("permission denied"))))
+
+	assert.Equal(test, rterror.ExitConfig, code)
+	assert.Equal(test, "cannot open config.yaml: read: permission denied\n", writer.String())
+}
+
+func TestExitNil(test *testing.T) {
+	var writer bytes.Buffer
+
+	code := -1
+
+	newTestExiter(&writer, &code).Main(func() error {
+		return nil
+	})
+
+	assert.Equal(test, rterror.ExitSuccess, code)
+	assert.Empty(test, writer.String())
+}
+
+func TestExitVerbose(test *testing.T) {
+	var writer bytes.Buffer
+
+	code := -1
+	err := rterror.New("error").SetFormat("{bold}{.FunctionBase}{reset}: {.String}").Wrap(rterror.New("wrapped").SetFormat("{.String}"))
+
+	newTestExiter(&writer, &code).SetVerbose(true).SetColor(rterror.ColorNever).Exit(err)
+
+	assert.Equal(test, rterror.ExitFailure, code)
+	assert.Equal(test, "TestExitVerbose: error\n`--wrapped\n", writer.String())
+}
+
+func TestExitVerboseEnv(test *testing.T) {
+	test.Setenv(rterror.VerboseEnv, "1")
+
+	assert.True(test, rterror.NewExiter().IsVerboseEnabled())
+}
+
+func TestExitCode(test *testing.T) {
+	exiter := rterror.NewExiter().SetExitCode("busy", 42)
+	child := exec.Command("sh", "-c", "exit 3").Run()
+
+	assert.Equal(test, rterror.ExitSuccess, exiter.ExitCode(nil))
+	assert.Equal(test, rterror.ExitFailure, exiter.ExitCode(errors.New("error")))
+	assert.Equal(test, rterror.ExitFailure, exiter.ExitCode(rterror.New("error").SetKind("unknown")))
+	assert.Equal(test, rterror.ExitNoInput, exiter.ExitCode(rterror.New("error").SetKind("not_found")))
+	assert.Equal(test, 42, exiter.ExitCode(rterror.New("error").Wrap(rterror.New("error").SetKind("busy"))))
+	assert.Equal(test, 5, exiter.ExitCode(rterror.New("error").SetKind("internal").Wrap(&exitCodeError{code: 5})))
+	assert.Equal(test, rterror.ExitFailure, exiter.ExitCode(&exitCodeError{code: -1}))
+	assert.Equal(test, 3, exiter.ExitCode(rterror.New("command failed").Wrap(child)))
+}
+
+func TestExitColor(test *testing.T) {
+	var writer bytes.Buffer
+
+	code := -1
+	err := rterror.New("{red}error{reset}")
+
+	newTestExiter(&writer, &code).SetColor(rterror.ColorAlways).Exit(err)
+	newTestExiter(&writer, &code).Exit(err)
+
+	assert.Equal(test, err.String()+"\nerror\n", writer.String())
+	assert.Equal(test, rterror.ColorAuto, rterror.NewExiter().GetColor())
+}
+
+func TestSetExiter(test *testing.T) {
+	var writer bytes.Buffer
+
+	code := -1
+	exiter := newTestExiter(&writer, &code)
+
+	rterror.SetExiter(exiter)
+	defer rterror.ResetExiter()
+
+	rterror.Main(func() error {
+		return rterror.New("error").SetKind("usage")
+	})
+
+	assert.Same(test, exiter, rterror.GetExiter())
+	assert.Equal(test, &writer, exiter.GetWriter())
+	assert.Equal(test, rterror.ExitUsage, code)
+	assert.Equal(test, "error\n", writer.String())
+
+	rterror.Exit(nil)
+
+	assert.Equal(test, rterror.ExitSuccess, code)
+}
+
+func TestSummary(test *testing.T) {
+	err := rterror.New("A").Wrap(fmt.Errorf("B: %w", rterror.New("C").SetFormat("{.String}")))
+
+	assert.Equal(test, "A: B: C", rterror.Summary(err))
+	assert.Empty(test, rterror.Summary(nil))
+}
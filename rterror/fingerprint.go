@@ -0,0 +1,44 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// FingerprintSize defines length of fingerprint string.
+const FingerprintSize = 16
+
+// Fingerprint returns runtime error fingerprint. It is a hexadecimal string
+// computed from function name and unformatted error message. It identifies
+// the place in code where runtime error was created and it doesn't change
+// when only line numbers or error arguments change.
+func (r *RuntimeError) Fingerprint() string {
+	return FingerprintOf(r.Function(), r._message)
+}
+
+// FingerprintOf returns fingerprint computed from full function name like
+// returned by the Function() method and unformatted error message. It allows
+// to compute fingerprint without creating runtime error, for example by tools.
+func FingerprintOf(function, message string) string {
+	hash := fnv.New64a()
+
+	_, _ = hash.Write([]byte(function))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(message))
+
+	return fmt.Sprintf("%0*x", FingerprintSize, hash.Sum64())
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func newFingerprintError(argument int) *rterror.RuntimeError {
+	return rterror.New("error {p0}", argument)
+}
+
+func TestFingerprint(test *testing.T) {
+	fingerprint := newFingerprintError(1).Fingerprint()
+
+	assert.Len(test, fingerprint, rterror.FingerprintSize)
+	assert.Equal(test, fingerprint, newFingerprintError(2).Fingerprint())
+}
+
+func TestFingerprintDifferent(test *testing.T) {
+	assert.NotEqual(test, rterror.New("error A").Fingerprint(), rterror.New("error B").Fingerprint())
+	assert.NotEqual(test, newFingerprintError(1).Fingerprint(), rterror.New("error {p0}", 1).Fingerprint())
+}
+
+func TestFingerprintOf(test *testing.T) {
+	err := newFingerprintError(1)
+
+	assert.Equal(test, err.Fingerprint(), rterror.FingerprintOf(err.Function(), err.Message()))
+	assert.Equal(test, err.Fingerprint(), rterror.FingerprintOf(
+		"gitlab.com/tymonx/go-error/rterror_test.newFingerprintError", "error {p0}"))
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Hook defines a function invoked with a runtime error object. It can be used
+// to observe runtime errors or to enrich them with fields or kind.
+type Hook func(r *RuntimeError)
+
+type hookEntry[T any] struct {
+	hook T
+}
+
+type hookChain[T any] struct {
+	mutex   sync.Mutex
+	entries atomic.Value
+}
+
+var (
+	gCreateHooks hookChain[Hook] // nolint: gochecknoglobals
+	gWrapHooks   hookChain[Hook] // nolint: gochecknoglobals
+)
+
+// OnCreate registers hook invoked every time a new runtime error object is
+// created by the New() or NewSkipCaller() functions. Hooks are invoked in
+// registration order. A panic from hook is recovered and never reaches the caller.
+// Hook must not create new runtime errors. It returns a function that unregisters hook.
+func OnCreate(hook Hook) (remove func()) {
+	return gCreateHooks.add(hook)
+}
+
+// OnWrap registers hook invoked every time an error is wrapped into runtime
+// error object by the Wrap() method. Wrapped error is available with the Unwrap()
+// method. It returns a function that unregisters hook.
+func OnWrap(hook Hook) (remove func()) {
+	return gWrapHooks.add(hook)
+}
+
+// ResetHooks unregisters all creation and wrap hooks.
+func ResetHooks() {
+	gCreateHooks.reset()
+	gWrapHooks.reset()
+}
+
+func (c *hookChain[T]) add(hook T) func() {
+	entry := &hookEntry[T]{
+		hook: hook,
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries := c.load()
+	updated := make([]*hookEntry[T], len(entries), len(entries)+1)
+
+	copy(updated, entries)
+	c.entries.Store(append(updated, entry))
+
+	return func() {
+		c.remove(entry)
+	}
+}
+
+func (c *hookChain[T]) remove(entry *hookEntry[T]) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries := c.load()
+	updated := make([]*hookEntry[T], 0, len(entries))
+
+	for _, e := range entries {
+		if e != entry {
+			updated = append(updated, e)
+		}
+	}
+
+	c.entries.Store(updated)
+}
+
+func (c *hookChain[T]) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries.Store([]*hookEntry[T](nil))
+}
+
+func (c *hookChain[T]) load() []*hookEntry[T] {
+	entries, _ := c.entries.Load().([]*hookEntry[T])
+	return entries
+}
+
+func (c *hookChain[T]) invoke(call func(hook T)) {
+	for _, entry := range c.load() {
+		entry.invoke(call)
+	}
+}
+
+func (e *hookEntry[T]) invoke(call func(hook T)) {
+	defer func() {
+		_ = recover() // Hook cannot panic the caller
+	}()
+
+	call(e.hook)
+}
+
+func invokeHooks(chain *hookChain[Hook], r *RuntimeError) {
+	chain.invoke(func(hook Hook) {
+		hook(r)
+	})
+}
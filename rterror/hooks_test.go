@@ -0,0 +1,153 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func TestOnCreate(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	var created *rterror.RuntimeError
+
+	rterror.OnCreate(func(r *rterror.RuntimeError) {
+		created = r
+	})
+
+	err := rterror.New("error")
+
+	assert.Same(test, err, created)
+}
+
+func TestOnCreateLocation(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	var function string
+
+	rterror.OnCreate(func(r *rterror.RuntimeError) {
+		function = r.Function()
+	})
+
+	rterror.New("error")
+
+	assert.Equal(test, "gitlab.com/tymonx/go-error/rterror_test.TestOnCreateLocation", function)
+}
+
+func TestOnCreateEnrich(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	rterror.OnCreate(func(r *rterror.RuntimeError) {
+		r.SetKind("internal").SetField("service", "foo")
+	})
+
+	err := rterror.New("error")
+
+	assert.Equal(test, rterror.Kind("internal"), err.GetKind())
+	assert.Equal(test, "foo", err.GetField("service"))
+}
+
+func TestOnCreatePanic(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	invoked := false
+
+	rterror.OnCreate(func(*rterror.RuntimeError) {
+		panic("hook")
+	})
+
+	rterror.OnCreate(func(*rterror.RuntimeError) {
+		invoked = true
+	})
+
+	assert.NotPanics(test, func() {
+		assert.Error(test, rterror.New("error"))
+	})
+
+	assert.True(test, invoked)
+}
+
+func TestOnCreateRemove(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	count := 0
+
+	remove := rterror.OnCreate(func(*rterror.RuntimeError) {
+		count++
+	})
+
+	rterror.New("error")
+	remove()
+	rterror.New("error")
+
+	assert.Equal(test, 1, count)
+}
+
+func TestOnWrap(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	var wrapped error
+
+	rterror.OnWrap(func(r *rterror.RuntimeError) {
+		wrapped = r.Unwrap()
+	})
+
+	rterror.New("error").Wrap(syscall.EAGAIN)
+
+	assert.Equal(test, syscall.EAGAIN, wrapped)
+}
+
+func TestHooksConcurrent(test *testing.T) {
+	defer rterror.ResetHooks()
+
+	const goroutines = 16
+
+	var created, wrapped int64
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			remove := rterror.OnCreate(func(*rterror.RuntimeError) {
+				atomic.AddInt64(&created, 1)
+			})
+
+			rterror.OnWrap(func(*rterror.RuntimeError) {
+				atomic.AddInt64(&wrapped, 1)
+			})
+
+			for j := 0; j < 100; j++ {
+				rterror.New("error {p0}", j).Wrap(syscall.EAGAIN)
+			}
+
+			remove()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.NotZero(test, atomic.LoadInt64(&created))
+	assert.NotZero(test, atomic.LoadInt64(&wrapped))
+}
@@ -0,0 +1,357 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"encoding/json"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Translation defines a translated error message template. It uses the same
+// placeholders as error message, so error arguments can be reordered. With
+// plural forms, the Plural field names argument like p0 or named argument key
+// which value selects plural form. The other plural form is used when selected
+// plural form is missing.
+type Translation struct {
+	Message string
+	Plural  string
+	Forms   map[PluralForm]string
+}
+
+// Catalog defines a message catalog with translated error message templates.
+// Translations are keyed by template ID set with the SetTemplateID() method,
+// by runtime error fingerprint or by unformatted error message.
+type Catalog struct {
+	mutex        sync.RWMutex
+	translations map[string]map[string]Translation
+	rules        map[string]PluralRule
+}
+
+type catalogFile struct {
+	Language string                 `json:"language"`
+	Messages map[string]Translation `json:"messages"`
+}
+
+type catalogHolder struct {
+	catalog *Catalog
+}
+
+var gCatalog atomic.Value // nolint: gochecknoglobals
+
+// NewCatalog creates a new empty message catalog object.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		translations: make(map[string]map[string]Translation),
+		rules:        make(map[string]PluralRule),
+	}
+}
+
+// SetCatalog sets package-wide message catalog used by the StringIn() method.
+func SetCatalog(catalog *Catalog) {
+	gCatalog.Store(catalogHolder{
+		catalog: catalog,
+	})
+}
+
+// GetCatalog returns package-wide message catalog. It returns nil if it was not set.
+func GetCatalog() *Catalog {
+	holder, _ := gCatalog.Load().(catalogHolder)
+	return holder.catalog
+}
+
+// ResetCatalog removes package-wide message catalog.
+func ResetCatalog() {
+	SetCatalog(nil)
+}
+
+// Add adds translated message template for provided language and key.
+func (c *Catalog) Add(language, key, message string) *Catalog {
+	return c.AddTranslation(language, key, Translation{
+		Message: message,
+	})
+}
+
+// AddTranslation adds translation for provided language and key.
+func (c *Catalog) AddTranslation(language, key string, translation Translation) *Catalog {
+	language = normalizeLanguage(language)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.translations[language] == nil {
+		c.translations[language] = make(map[string]Translation)
+	}
+
+	c.translations[language][key] = translation
+
+	return c
+}
+
+// SetPluralRule sets plural rule for provided language. It overrides built-in
+// plural rule returned by the PluralRuleOf() function.
+func (c *Catalog) SetPluralRule(language string, rule PluralRule) *Catalog {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.rules[normalizeLanguage(language)] = rule
+
+	return c
+}
+
+// Languages returns sorted list of languages with translations.
+func (c *Catalog) Languages() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	languages := make([]string, 0, len(c.translations))
+
+	for language := range c.translations {
+		languages = append(languages, language)
+	}
+
+	sort.Strings(languages)
+
+	return languages
+}
+
+// LoadJSON loads translations from JSON catalog file. Translation is a string
+// or an object with the plural key and plural forms:
+//
+//  {
+//      "language": "pl",
+//      "messages": {
+//          "User {p0} not found": "Nie znaleziono użytkownika {p0}",
+//          "Found {p0} errors": {
+//              "plural": "p0",
+//              "one": "Znaleziono {p0} błąd",
+//              "few": "Znaleziono {p0} błędy",
+//              "many": "Znaleziono {p0} błędów"
+//          }
+//      }
+//  }
+func (c *Catalog) LoadJSON(data []byte) error {
+	var file catalogFile
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return NewSkipCaller(SkipCall, "cannot decode message catalog").Wrap(err)
+	}
+
+	if file.Language == "" {
+		return NewSkipCaller(SkipCall, "message catalog without language")
+	}
+
+	for key, translation := range file.Messages {
+		c.AddTranslation(file.Language, key, translation)
+	}
+
+	return nil
+}
+
+// LoadFS loads translations from all JSON catalog files matching provided
+// patterns in file system. It can be used with embedded files:
+//
+//  //go:embed locales/*.json
+//  var locales embed.FS
+//
+//  err := catalog.LoadFS(locales, "locales/*.json")
+func (c *Catalog) LoadFS(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		paths, err := fs.Glob(fsys, pattern)
+
+		if err != nil {
+			return New("invalid message catalog pattern {p0}", pattern).Wrap(err)
+		}
+
+		for _, path := range paths {
+			data, err := fs.ReadFile(fsys, path)
+
+			if err != nil {
+				return New("cannot read message catalog {p0}", path).Wrap(err)
+			}
+
+			if err := c.LoadJSON(data); err != nil {
+				return New("cannot load message catalog {p0}", path).Wrap(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Translate returns translated message template of runtime error for provided
+// language. Language tag like pt-BR falls back to base language pt. It returns
+// false if there is no translation.
+func (c *Catalog) Translate(language string, r *RuntimeError) (string, bool) {
+	language = normalizeLanguage(language)
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for _, tag := range []string{language, baseLanguage(language)} {
+		translations, ok := c.translations[tag]
+
+		if !ok {
+			continue
+		}
+
+		for _, key := range []string{r.templateID, r.Fingerprint(), r._message} {
+			if translation, ok := translations[key]; ok && (key != "") {
+				return c.pick(tag, translation, r._arguments)
+			}
+		}
+	}
+
+	return "", false
+}
+
+// SetTemplateID sets template ID used to find error message translations in message catalog.
+func (r *RuntimeError) SetTemplateID(id string) *RuntimeError {
+	r.templateID = id
+	return r
+}
+
+// TemplateID returns template ID used to find error message translations in
+// message catalog. It returns fingerprint if template ID was not set.
+func (r *RuntimeError) TemplateID() string {
+	if r.templateID != "" {
+		return r.templateID
+	}
+
+	return r.Fingerprint()
+}
+
+// StringIn returns error message string translated to provided language using
+// package-wide message catalog. Missing translation falls back to the String()
+// method. Translation that cannot be formatted is handled according to failure
+// mode like in the String() method.
+func (r *RuntimeError) StringIn(language string) string {
+	catalog := GetCatalog()
+
+	if catalog == nil {
+		return r.String()
+	}
+
+	message, ok := catalog.Translate(language, r)
+
+	if !ok {
+		return r.String()
+	}
+
+	formatted, err := r.messageFormatter().Format(message, redactArguments(r._arguments)...)
+
+	if err != nil {
+		return r.failback(message, err)
+	}
+
+	return formatted
+}
+
+// UnmarshalJSON decodes translation from JSON string or object with plural forms.
+func (t *Translation) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &t.Message); err == nil {
+		return nil
+	}
+
+	var forms map[string]string
+
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return New("translation must be string or object with plural forms").Wrap(err)
+	}
+
+	t.Plural = forms["plural"]
+	t.Forms = make(map[PluralForm]string, len(forms))
+
+	for form, message := range forms {
+		if form != "plural" {
+			t.Forms[PluralForm(form)] = message
+		}
+	}
+
+	return nil
+}
+
+func (c *Catalog) pick(language string, translation Translation, arguments []interface{}) (string, bool) {
+	if translation.Plural == "" {
+		return translation.Message, translation.Message != ""
+	}
+
+	count, ok := countOf(translation.Plural, arguments)
+
+	if !ok {
+		return "", false
+	}
+
+	rule, ok := c.rules[language]
+
+	if !ok {
+		rule = PluralRuleOf(language)
+	}
+
+	if message, ok := translation.Forms[rule(count)]; ok {
+		return message, true
+	}
+
+	message, ok := translation.Forms[PluralOther]
+
+	return message, ok
+}
+
+// countOf returns numeric value of positional argument pN or named argument from map argument.
+func countOf(name string, arguments []interface{}) (float64, bool) {
+	if strings.HasPrefix(name, "p") {
+		if index, err := strconv.Atoi(name[1:]); (err == nil) && (index >= 0) && (index < len(arguments)) {
+			return toFloat(arguments[index])
+		}
+	}
+
+	for _, argument := range arguments {
+		value := reflect.ValueOf(argument)
+
+		if (value.Kind() != reflect.Map) || (value.Type().Key().Kind() != reflect.String) {
+			continue
+		}
+
+		if found := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key())); found.IsValid() {
+			return toFloat(found.Interface())
+		}
+	}
+
+	return 0, false
+}
+
+func toFloat(argument interface{}) (float64, bool) {
+	value := reflect.ValueOf(unredact(argument))
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ReplaceAll(strings.ToLower(language), "_", "-")
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"embed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+//go:embed testdata/locales/*.json
+var locales embed.FS
+
+func setCatalog(test *testing.T) *rterror.Catalog {
+	catalog := rterror.NewCatalog()
+
+	assert.NoError(test, catalog.LoadFS(locales, "testdata/locales/*.json"))
+
+	rterror.SetCatalog(catalog)
+	test.Cleanup(rterror.ResetCatalog)
+
+	return catalog
+}
+
+func TestStringIn(test *testing.T) {
+	catalog := setCatalog(test)
+
+	err := rterror.New("user {p0} not found in {p1}", "bob", "db")
+
+	assert.Equal(test, []string{"en-gb", "pl"}, catalog.Languages())
+	assert.Equal(test, "w db nie znaleziono użytkownika bob", err.StringIn("pl"))
+	assert.Equal(test, "w db nie znaleziono użytkownika bob", err.StringIn("pl_PL"))
+	assert.Equal(test, "user bob not found in db", err.StringIn("de"))
+	assert.Equal(test, "invalid colour red", rterror.New("invalid color {p0}", "red").StringIn("en-GB"))
+	assert.Equal(test, "invalid color red", rterror.New("invalid color {p0}", "red").StringIn("en"))
+	assert.Equal(test, "przekroczono limit 5", rterror.New("quota {p0} exceeded", 5).SetTemplateID("quota").StringIn("pl"))
+}
+
+func TestStringInPlural(test *testing.T) {
+	setCatalog(test)
+
+	for count, expected := range map[int]string{
+		1:  "znaleziono 1 błąd",
+		3:  "znaleziono 3 błędy",
+		5:  "znaleziono 5 błędów",
+		13: "znaleziono 13 błędów",
+		22: "znaleziono 22 błędy",
+	} {
+		err := rterror.New("found {count} errors", formatter.Named{"count": count})
+
+		assert.Equal(test, expected, err.StringIn("pl"))
+	}
+
+	assert.Equal(test, "found x errors", rterror.New("found {count} errors", formatter.Named{"count": "x"}).StringIn("pl"))
+}
+
+func TestStringInFingerprint(test *testing.T) {
+	err := rterror.New("error {p0}", 1)
+
+	rterror.SetCatalog(rterror.NewCatalog().Add("de", err.TemplateID(), "Fehler {p0}"))
+	defer rterror.ResetCatalog()
+
+	assert.Equal(test, err.Fingerprint(), err.TemplateID())
+	assert.Equal(test, "Fehler 1", err.StringIn("de-AT"))
+	assert.Equal(test, "custom", err.SetTemplateID("custom").TemplateID())
+}
+
+func TestStringInFailure(test *testing.T) {
+	var reported *rterror.FormatError
+
+	defer rterror.ResetFailureMode()
+	defer rterror.OnFormatFailure(func(r *rterror.RuntimeError, err *rterror.FormatError) {
+		reported = err
+	})()
+
+	err := rterror.New("error {p0}", 1)
+
+	rterror.SetCatalog(rterror.NewCatalog().Add("de", err.TemplateID(), "Fehler {p1}"))
+	defer rterror.ResetCatalog()
+
+	rterror.SetFailureMode(rterror.FailureReport)
+
+	assert.Equal(test, "error {p0}", err.StringIn("de"))
+	assert.NotNil(test, reported)
+	assert.Equal(test, "Fehler {p1}", reported.Format)
+
+	rterror.SetFailureMode(rterror.FailurePanic)
+
+	assert.Panics(test, func() {
+		err.StringIn("de")
+	})
+}
+
+func TestCatalogPluralRule(test *testing.T) {
+	catalog := rterror.NewCatalog().SetPluralRule("en", func(count float64) rterror.PluralForm {
+		if count == 0 {
+			return rterror.PluralZero
+		}
+
+		return rterror.PluralOther
+	}).AddTranslation("en", "{p0} files", rterror.Translation{
+		Plural: "p0",
+		Forms: map[rterror.PluralForm]string{
+			rterror.PluralZero:  "no files ({p0})",
+			rterror.PluralOther: "{p0} files",
+		},
+	})
+
+	rterror.SetCatalog(catalog)
+	defer rterror.ResetCatalog()
+
+	assert.Equal(test, "no files (0)", rterror.New("{p0} files", 0).StringIn("en"))
+	assert.Equal(test, "1 files", rterror.New("{p0} files", 1).StringIn("en"))
+}
+
+func TestCatalogLoadJSON(test *testing.T) {
+	catalog := rterror.NewCatalog()
+
+	assert.Error(test, catalog.LoadJSON([]byte(`{`)))
+	assert.Error(test, catalog.LoadJSON([]byte(`{"messages": {}}`)))
+	assert.Error(test, catalog.LoadJSON([]byte(`{"language": "en", "messages": {"a": 1}}`)))
+	assert.Error(test, catalog.LoadFS(locales, "["))
+	assert.Nil(test, rterror.GetCatalog())
+	assert.Equal(test, "error", rterror.New("error").StringIn("pl"))
+}
+
+func TestPluralRuleOf(test *testing.T) {
+	assert.Equal(test, rterror.PluralOne, rterror.PluralRuleOf("en-US")(1))
+	assert.Equal(test, rterror.PluralOther, rterror.PluralRuleOf("en")(1.5))
+	assert.Equal(test, rterror.PluralOne, rterror.PluralRuleOf("fr")(0))
+	assert.Equal(test, rterror.PluralOne, rterror.PluralRuleOf("ru")(21))
+	assert.Equal(test, rterror.PluralMany, rterror.PluralRuleOf("uk")(11))
+	assert.Equal(test, rterror.PluralFew, rterror.PluralRuleOf("cs")(3))
+	assert.Equal(test, rterror.PluralOther, rterror.PluralRuleOf("ja")(1))
+}
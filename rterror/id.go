@@ -0,0 +1,94 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// IDSize defines length of unique identifier string.
+const IDSize = 26
+
+// Crockford's Base32 alphabet, it preserves lexicographical order.
+const idAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// idGenerator generates 128-bit identifiers composed of 48-bit timestamp in
+// milliseconds and 80-bit random part. Within the same millisecond random part
+// is incremented, so identifiers are unique and sortable by creation time.
+type idGenerator struct {
+	mutex     sync.Mutex
+	timestamp uint64
+	high      uint16
+	low       uint64
+}
+
+var gIDGenerator idGenerator // nolint: gochecknoglobals
+
+func newID(now time.Time) string {
+	return gIDGenerator.generate(now)
+}
+
+func (g *idGenerator) generate(now time.Time) string {
+	timestamp := uint64(now.UnixNano()/int64(time.Millisecond)) & (1<<48 - 1)
+
+	g.mutex.Lock()
+
+	if timestamp > g.timestamp {
+		var random [10]byte
+
+		_, _ = rand.Read(random[:])
+
+		g.timestamp = timestamp
+		g.high = binary.BigEndian.Uint16(random[:2])
+		g.low = binary.BigEndian.Uint64(random[2:])
+	} else {
+		g.low++
+
+		if g.low == 0 {
+			g.high++
+		}
+	}
+
+	var id [16]byte
+
+	binary.BigEndian.PutUint16(id[0:], uint16(g.timestamp>>32))
+	binary.BigEndian.PutUint32(id[2:], uint32(g.timestamp))
+	binary.BigEndian.PutUint16(id[6:], g.high)
+	binary.BigEndian.PutUint64(id[8:], g.low)
+
+	g.mutex.Unlock()
+
+	return encodeID(id)
+}
+
+func encodeID(id [16]byte) string {
+	var encoded [IDSize]byte
+
+	high := binary.BigEndian.Uint64(id[:8])
+	low := binary.BigEndian.Uint64(id[8:])
+
+	// 128 bits are encoded using 26 characters with 5 bits each, the first
+	// character holds only 3 most significant bits
+	for index := IDSize - 1; index >= 0; index-- {
+		encoded[index] = idAlphabet[low&0x1F]
+		low = (low >> 5) | (high << 59)
+		high >>= 5
+	}
+
+	return string(encoded[:])
+}
@@ -14,10 +14,37 @@
 
 package rterror
 
+import (
+	"time"
+)
+
 type marshal struct {
-	Line      int           `json:"line"`
-	File      string        `json:"file"`
-	Function  string        `json:"function"`
-	Message   string        `json:"message"`
-	Arguments []interface{} `json:"arguments"`
+	Line      int                    `json:"line"`
+	File      string                 `json:"file"`
+	Function  string                 `json:"function"`
+	Message   string                 `json:"message"`
+	Arguments []interface{}          `json:"arguments"`
+	Public    string                 `json:"public,omitempty"`
+	Kind      Kind                   `json:"kind,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Time      *time.Time             `json:"time,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+}
+
+func marshalTime(l locator) *time.Time {
+	t := l.Time()
+
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func (r *RuntimeError) marshalPublic() string {
+	if r.publicMessage == "" {
+		return ""
+	}
+
+	return r.PublicString()
 }
@@ -0,0 +1,305 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"unicode/utf8"
+
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+// MessageFormatter defines an interface of formatter backend that formats
+// error message with error arguments. It is used by the String() method and
+// by the TopError() method to format the {.String} field. The formatter object
+// from the Go Formatter library implements this interface and it is the default.
+type MessageFormatter interface {
+	Format(message string, arguments ...interface{}) (string, error)
+}
+
+// SprintfFormatter defines a message formatter that uses the fmt.Sprintf()
+// function with format verbs %.
+type SprintfFormatter struct{}
+
+// TemplateFormatter defines a message formatter that uses the text/template
+// package. Template data is a map with positional arguments p0, p1, ..., pN
+// and with keys of all arguments that are maps with string keys.
+type TemplateFormatter struct {
+	functions template.FuncMap
+}
+
+type formatterHolder struct {
+	formatter MessageFormatter
+}
+
+var (
+	gFormatter        atomic.Value                       // nolint: gochecknoglobals
+	gDefaultFormatter MessageFormatter = formatter.New() // nolint: gochecknoglobals
+)
+
+// SetFormatter sets package-wide message formatter used by runtime errors without own message formatter.
+func SetFormatter(f MessageFormatter) {
+	gFormatter.Store(formatterHolder{
+		formatter: f,
+	})
+}
+
+// GetFormatter returns package-wide message formatter. By default, it is
+// the formatter object from the Go Formatter library shared by all runtime
+// errors, modifying it affects all runtime errors without own message formatter.
+func GetFormatter() MessageFormatter {
+	if holder, ok := gFormatter.Load().(formatterHolder); ok && (holder.formatter != nil) {
+		return holder.formatter
+	}
+
+	return gDefaultFormatter
+}
+
+// ResetFormatter resets package-wide message formatter to default value that
+// is the formatter object from the Go Formatter library.
+func ResetFormatter() {
+	SetFormatter(nil)
+}
+
+// Format formats message using the fmt.Sprintf() function. It returns error
+// if message contains invalid format verbs or arguments don't match them.
+// Errors are detected from message, so argument values may contain "%!".
+func (SprintfFormatter) Format(message string, arguments ...interface{}) (string, error) {
+	if marker := (&verbScanner{message: message, arguments: arguments}).scan(); marker != "" {
+		return "", fmt.Errorf("invalid format verb or argument: %s", marker)
+	}
+
+	return fmt.Sprintf(message, arguments...), nil
+}
+
+// NewTemplateFormatter creates a new template formatter object.
+func NewTemplateFormatter() *TemplateFormatter {
+	return &TemplateFormatter{
+		functions: make(template.FuncMap),
+	}
+}
+
+// AddFunctions adds template functions.
+func (t *TemplateFormatter) AddFunctions(functions template.FuncMap) *TemplateFormatter {
+	for name, function := range functions {
+		t.functions[name] = function
+	}
+
+	return t
+}
+
+// Format formats message using the text/template package. Missing keys are reported as error.
+func (t *TemplateFormatter) Format(message string, arguments ...interface{}) (string, error) {
+	parsed, err := template.New("").Option("missingkey=error").Funcs(t.functions).Parse(message)
+
+	if err != nil {
+		return "", err
+	}
+
+	data := make(map[string]interface{}, len(arguments))
+
+	for position, argument := range arguments {
+		data[formatter.DefaultPlaceholder+strconv.Itoa(position)] = argument
+
+		if value := reflect.ValueOf(argument); (value.Kind() == reflect.Map) && (value.Type().Key().Kind() == reflect.String) {
+			for _, key := range value.MapKeys() {
+				data[key.String()] = value.MapIndex(key).Interface()
+			}
+		}
+	}
+
+	var builder strings.Builder
+
+	if err := parsed.Execute(&builder, data); err != nil {
+		return "", err
+	}
+
+	return builder.String(), nil
+}
+
+// verbScanner scans format verbs like the fmt.Sprintf() function does.
+type verbScanner struct {
+	message   string
+	arguments []interface{}
+	index     int
+	argument  int
+	reordered bool
+	good      bool
+}
+
+// scan returns the first error marker that the fmt.Sprintf() function would
+// print for message and arguments or empty string if there are no errors.
+func (s *verbScanner) scan() string {
+	for s.index < len(s.message) {
+		if s.message[s.index] != '%' {
+			s.index++
+			continue
+		}
+
+		s.index++
+
+		if marker := s.verb(); marker != "" {
+			return marker
+		}
+	}
+
+	if !s.reordered && (s.argument < len(s.arguments)) {
+		return "%!(EXTRA)"
+	}
+
+	return ""
+}
+
+// verb scans flags, width, precision and verb after the % character.
+func (s *verbScanner) verb() string {
+	for (s.index < len(s.message)) && strings.ContainsRune("#0+- ", rune(s.message[s.index])) {
+		s.index++
+	}
+
+	s.good = true
+	indexed := s.argumentIndex()
+
+	if s.next('*') {
+		if !s.starArgument("%*s") {
+			return "%!(BADWIDTH)"
+		}
+
+		indexed = false
+	} else if s.number() && indexed {
+		s.good = false
+	}
+
+	if ((s.index + 1) < len(s.message)) && s.next('.') {
+		if indexed {
+			s.good = false
+		}
+
+		indexed = s.argumentIndex()
+
+		if s.next('*') {
+			if !s.starArgument("%.*s") {
+				return "%!(BADPREC)"
+			}
+
+			indexed = false
+		} else {
+			s.number()
+		}
+	}
+
+	if !indexed {
+		s.argumentIndex()
+	}
+
+	if s.index >= len(s.message) {
+		return "%!(NOVERB)"
+	}
+
+	verb, size := utf8.DecodeRuneInString(s.message[s.index:])
+	s.index += size
+
+	switch {
+	case verb == '%':
+		return ""
+	case !s.good:
+		return "%!" + string(verb) + "(BADINDEX)"
+	case s.argument >= len(s.arguments):
+		return "%!" + string(verb) + "(MISSING)"
+	}
+
+	argument := s.arguments[s.argument]
+	s.argument++
+
+	return badVerb(verb, argument)
+}
+
+// next skips character and returns true if it is the next character.
+func (s *verbScanner) next(character byte) bool {
+	if (s.index < len(s.message)) && (s.message[s.index] == character) {
+		s.index++
+		return true
+	}
+
+	return false
+}
+
+// number skips decimal number and returns true if it was present.
+func (s *verbScanner) number() bool {
+	start := s.index
+
+	for (s.index < len(s.message)) && (s.message[s.index] >= '0') && (s.message[s.index] <= '9') {
+		s.index++
+	}
+
+	return s.index != start
+}
+
+// argumentIndex scans explicit argument index [n] and returns true if it is valid.
+func (s *verbScanner) argumentIndex() bool {
+	if !s.next('[') {
+		return false
+	}
+
+	s.reordered = true
+	end := strings.IndexByte(s.message[s.index:], ']')
+
+	if end == -1 {
+		s.good = false
+		return false
+	}
+
+	digits := s.message[s.index : s.index+end]
+	index, err := strconv.Atoi(digits)
+	s.index += end + 1
+
+	if (err != nil) || (strings.Trim(digits, "0123456789") != "") || (index < 1) || (index > len(s.arguments)) {
+		s.good = false
+		return false
+	}
+
+	s.argument = index - 1
+
+	return true
+}
+
+// starArgument consumes argument used as width or precision and returns true if it is valid.
+func (s *verbScanner) starArgument(format string) bool {
+	if s.argument >= len(s.arguments) {
+		return false
+	}
+
+	argument := s.arguments[s.argument]
+	s.argument++
+
+	return !strings.HasPrefix(fmt.Sprintf(format, argument, ""), "%!")
+}
+
+// badVerb returns error marker if verb is invalid for argument. Argument
+// values may contain markers, so the number of markers printed with the verb
+// is compared with the number of markers printed with the %v verb.
+func badVerb(verb rune, argument interface{}) string {
+	marker := "%!" + string(verb) + "("
+
+	if strings.Count(fmt.Sprintf("%"+string(verb), argument), marker) > strings.Count(fmt.Sprint(argument), marker) {
+		return marker + fmt.Sprintf("%T", argument) + ")"
+	}
+
+	return ""
+}
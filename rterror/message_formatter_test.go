@@ -0,0 +1,105 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+type user struct {
+	Name string
+}
+
+func TestMessageFormatterDefault(test *testing.T) {
+	assert.IsType(test, formatter.New(), rterror.GetFormatter())
+	assert.Equal(test, "A 3 foo", rterror.New("A {p} {p}", 3, "foo").String())
+	assert.Equal(test, "A foo 3", rterror.New("A {p1} {p0}", 3, "foo").String())
+	assert.Equal(test, "A bar", rterror.New("A {name}", formatter.Named{"name": "bar"}).String())
+	assert.Equal(test, "A baz", rterror.New("A {.Name}", &user{Name: "baz"}).String())
+	assert.Equal(test, "A 3 true", rterror.New("A", 3, true).String())
+}
+
+func TestMessageFormatterTopError(test *testing.T) {
+	err := rterror.New("A %d", 5).SetFormat("{.FunctionBase}: {.String}").SetFormatter(rterror.SprintfFormatter{})
+
+	assert.Equal(test, "TestMessageFormatterTopError: A 5", err.Error())
+}
+
+func TestSprintfFormatter(test *testing.T) {
+	formatted, err := rterror.SprintfFormatter{}.Format("A %s %03d", "foo", 7)
+
+	assert.NoError(test, err)
+	assert.Equal(test, "A foo 007", formatted)
+
+	invalid := "A %d %d"
+
+	_, err = rterror.SprintfFormatter{}.Format(invalid, 7)
+
+	assert.Error(test, err)
+
+	formatted, err = rterror.SprintfFormatter{}.Format("A %s %[1]q %*d", "%!d(MISSING)", 3, 7)
+
+	assert.NoError(test, err)
+	assert.Equal(test, `A %!d(MISSING) "%!d(MISSING)"   7`, formatted)
+
+	for message, arguments := range map[string][]interface{}{
+		"A %d":      {"foo"},
+		"A %s":      {[]int{1}},
+		"A %w":      {errors.New("%!w(x)")},
+		"A %[2]d":   {1},
+		"A %*d":     {"foo", 1},
+		"A %.*d":    {-1, 1},
+		"A %":       nil,
+		"A":         {1},
+		"A %d %!d(": {1},
+	} {
+		_, err = rterror.SprintfFormatter{}.Format(message, arguments...)
+
+		assert.Error(test, err, message)
+	}
+}
+
+func TestTemplateFormatter(test *testing.T) {
+	setFallback(test)
+
+	f := rterror.NewTemplateFormatter().AddFunctions(template.FuncMap{
+		"upper": strings.ToUpper,
+	})
+
+	err := rterror.New(`A {{.p1}} {{.name | upper}}`, formatter.Named{"name": "bar"}, 3).SetFormatter(f)
+
+	assert.Equal(test, "A 3 BAR", err.String())
+	assert.Equal(test, "A {{.p2}}", rterror.New("A {{.p2}}").SetFormatter(f).String()) // Missing key
+}
+
+func TestSetFormatter(test *testing.T) {
+	defer rterror.ResetFormatter()
+
+	rterror.SetFormatter(rterror.SprintfFormatter{})
+
+	err := rterror.New("A %v", 3)
+
+	assert.Equal(test, rterror.SprintfFormatter{}, rterror.GetFormatter())
+	assert.Equal(test, "A 3", err.String())
+	assert.Equal(test, "A %v 3", err.SetFormatter(formatter.New()).String())
+	assert.Equal(test, "A 3", err.ResetFormatter().String())
+}
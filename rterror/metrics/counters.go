@@ -0,0 +1,103 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type counters struct {
+	mutex  sync.Mutex
+	limit  int
+	length int
+	values sync.Map
+}
+
+func newCounters(limit int) *counters {
+	return &counters{
+		limit: limit,
+	}
+}
+
+func (c *counters) setLimit(limit int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.limit = limit
+}
+
+func (c *counters) getLimit() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.limit
+}
+
+func (c *counters) increment(label string) {
+	if value, ok := c.values.Load(label); ok {
+		atomic.AddUint64(value.(*uint64), 1)
+		return
+	}
+
+	atomic.AddUint64(c.create(label), 1)
+}
+
+func (c *counters) create(label string) *uint64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if value, ok := c.values.Load(label); ok {
+		return value.(*uint64)
+	}
+
+	if c.length >= c.limit {
+		label = OverflowLabel
+
+		if value, ok := c.values.Load(label); ok {
+			return value.(*uint64)
+		}
+	} else {
+		c.length++
+	}
+
+	value := new(uint64)
+	c.values.Store(label, value)
+
+	return value
+}
+
+func (c *counters) snapshot() map[string]uint64 {
+	snapshot := make(map[string]uint64)
+
+	c.values.Range(func(key, value interface{}) bool {
+		snapshot[key.(string)] = atomic.LoadUint64(value.(*uint64))
+		return true
+	})
+
+	return snapshot
+}
+
+func (c *counters) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values.Range(func(key, _ interface{}) bool {
+		c.values.Delete(key)
+		return true
+	})
+
+	c.length = 0
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics implements in-process registry of runtime error counters.
+// Counters are grouped by fingerprint, kind, package and function and they are
+// exposed with the expvar package and with HTTP handler using the Prometheus
+// text exposition format.
+//
+// Counters are updated explicitly with the Report() method when errors are
+// reported or with runtime error creation hooks. Creation hooks run before kind
+// is set by chained method calls like New().SetKind(), so with hooks the kind
+// label is not available and runtime errors are counted under the empty kind.
+package metrics
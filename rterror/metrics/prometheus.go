@@ -0,0 +1,86 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType defines HTTP content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var gLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`) // nolint: gochecknoglobals
+
+// ServeHTTP writes all counters using the Prometheus text exposition format.
+func (r *Registry) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", ContentType)
+
+	if err := r.WritePrometheus(writer); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WritePrometheus writes all counters to writer using the Prometheus text exposition format.
+func (r *Registry) WritePrometheus(writer io.Writer) error {
+	snapshot := r.Snapshot()
+	buffered := bufio.NewWriter(writer)
+
+	writeHeader(buffered, MetricPrefix+"_total", "Total number of created runtime errors.")
+	writeSample(buffered, MetricPrefix+"_total", "", "", snapshot.Total)
+
+	writeFamily(buffered, "fingerprint", snapshot.Fingerprint)
+	writeFamily(buffered, "kind", snapshot.Kind)
+	writeFamily(buffered, "package", snapshot.Package)
+	writeFamily(buffered, "function", snapshot.Function)
+
+	return buffered.Flush()
+}
+
+func writeFamily(writer *bufio.Writer, label string, values map[string]uint64) {
+	name := MetricPrefix + "_by_" + label + "_total"
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	writeHeader(writer, name, "Number of created runtime errors by "+label+".")
+
+	for _, key := range keys {
+		writeSample(writer, name, label, key, values[key])
+	}
+}
+
+func writeHeader(writer *bufio.Writer, name, help string) {
+	_, _ = writer.WriteString("# HELP " + name + " " + help + "\n")
+	_, _ = writer.WriteString("# TYPE " + name + " counter\n")
+}
+
+func writeSample(writer *bufio.Writer, name, label, value string, count uint64) {
+	_, _ = writer.WriteString(name)
+
+	if label != "" {
+		_, _ = writer.WriteString("{" + label + `="` + gLabelReplacer.Replace(value) + `"}`)
+	}
+
+	_, _ = writer.WriteString(" " + strconv.FormatUint(count, 10) + "\n")
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/metrics"
+)
+
+func TestRegistryServeHTTP(test *testing.T) {
+	registry := metrics.New()
+
+	err := rterror.New("error").SetKind(`a"b`)
+	registry.Observe(err)
+
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	response, e := http.Get(server.URL)
+	assert.NoError(test, e)
+
+	defer response.Body.Close()
+
+	body, e := ioutil.ReadAll(response.Body)
+	assert.NoError(test, e)
+
+	assert.Equal(test, metrics.ContentType, response.Header.Get("Content-Type"))
+	assert.Equal(test, `# HELP rterror_errors_total Total number of created runtime errors.
+# TYPE rterror_errors_total counter
+rterror_errors_total 1
+# HELP rterror_errors_by_fingerprint_total Number of created runtime errors by fingerprint.
+# TYPE rterror_errors_by_fingerprint_total counter
+rterror_errors_by_fingerprint_total{fingerprint="`+err.Fingerprint()+`"} 1
+# HELP rterror_errors_by_kind_total Number of created runtime errors by kind.
+# TYPE rterror_errors_by_kind_total counter
+rterror_errors_by_kind_total{kind="a\"b"} 1
+# HELP rterror_errors_by_package_total Number of created runtime errors by package.
+# TYPE rterror_errors_by_package_total counter
+rterror_errors_by_package_total{package="gitlab.com/tymonx/go-error/rterror/metrics_test"} 1
+# HELP rterror_errors_by_function_total Number of created runtime errors by function.
+# TYPE rterror_errors_by_function_total counter
+rterror_errors_by_function_total{function="gitlab.com/tymonx/go-error/rterror/metrics_test.TestRegistryServeHTTP"} 1
+`, string(body))
+}
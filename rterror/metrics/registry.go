@@ -0,0 +1,172 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"expvar"
+	"sync"
+	"sync/atomic"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// These constants define default values used by registry.
+const (
+	DefaultLimit  = 1000
+	MetricPrefix  = "rterror_errors"
+	OverflowLabel = "__overflow__"
+)
+
+// Snapshot defines a point-in-time copy of all registry counters.
+type Snapshot struct {
+	Total       uint64            `json:"total"`
+	Fingerprint map[string]uint64 `json:"fingerprint"`
+	Kind        map[string]uint64 `json:"kind"`
+	Package     map[string]uint64 `json:"package"`
+	Function    map[string]uint64 `json:"function"`
+}
+
+// Registry defines a registry of runtime error counters. Each group of counters
+// is limited to the given number of distinct label values. Runtime errors with
+// label values above the limit are counted in the overflow counter.
+type Registry struct {
+	total       uint64
+	mutex       sync.Mutex
+	remove      func()
+	fingerprint *counters
+	kind        *counters
+	_package    *counters
+	function    *counters
+}
+
+// New creates a new registry object with default cardinality limit.
+func New() *Registry {
+	return &Registry{
+		fingerprint: newCounters(DefaultLimit),
+		kind:        newCounters(DefaultLimit),
+		_package:    newCounters(DefaultLimit),
+		function:    newCounters(DefaultLimit),
+	}
+}
+
+// SetLimit sets maximum number of distinct label values for each group of counters.
+// It should be set before registry starts observing runtime errors.
+func (r *Registry) SetLimit(limit int) *Registry {
+	for _, c := range r.groups() {
+		c.setLimit(limit)
+	}
+
+	return r
+}
+
+// GetLimit returns maximum number of distinct label values for each group of counters.
+func (r *Registry) GetLimit() int {
+	return r.fingerprint.getLimit()
+}
+
+// Register registers registry with the runtime error creation hooks.
+// Registering already registered registry does nothing. Runtime errors are
+// observed when they are created, before kind is set by chained method calls
+// like New().SetKind(). The kind label is not available, runtime errors are
+// counted under the empty kind unless creation hooks set kind. Use the Report()
+// method instead when kind counters are needed.
+func (r *Registry) Register() *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.remove == nil {
+		r.remove = rterror.OnCreate(r.Observe)
+	}
+
+	return r
+}
+
+// Unregister unregisters registry from the runtime error creation hooks.
+func (r *Registry) Unregister() *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.remove != nil {
+		r.remove()
+		r.remove = nil
+	}
+
+	return r
+}
+
+// Observe increments all counters for provided runtime error. Runtime error
+// should not be modified concurrently.
+func (r *Registry) Observe(e *rterror.RuntimeError) {
+	atomic.AddUint64(&r.total, 1)
+
+	r.fingerprint.increment(e.Fingerprint())
+	r.kind.increment(string(e.GetKind()))
+	r._package.increment(e.Package())
+	r.function.increment(e.Function())
+}
+
+// Report increments all counters for the first runtime error from error chain.
+// It should be called when error is reported, after its kind was set, and it
+// should not be used together with the Register() method to avoid counting
+// the same runtime error twice. Errors without runtime error are ignored.
+func (r *Registry) Report(err error) {
+	var e *rterror.RuntimeError
+
+	if errors.As(err, &e) && (e != nil) {
+		r.Observe(e)
+	}
+}
+
+// Total returns total number of observed runtime errors.
+func (r *Registry) Total() uint64 {
+	return atomic.LoadUint64(&r.total)
+}
+
+// Snapshot returns copy of all counters.
+func (r *Registry) Snapshot() *Snapshot {
+	return &Snapshot{
+		Total:       r.Total(),
+		Fingerprint: r.fingerprint.snapshot(),
+		Kind:        r.kind.snapshot(),
+		Package:     r._package.snapshot(),
+		Function:    r.function.snapshot(),
+	}
+}
+
+// Reset resets all counters.
+func (r *Registry) Reset() *Registry {
+	atomic.StoreUint64(&r.total, 0)
+
+	for _, c := range r.groups() {
+		c.reset()
+	}
+
+	return r
+}
+
+// Publish publishes registry snapshot as expvar variable with provided name.
+// Like the expvar.Publish() function, it panics if name is already registered.
+func (r *Registry) Publish(name string) *Registry {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return r.Snapshot()
+	}))
+
+	return r
+}
+
+func (r *Registry) groups() []*counters {
+	return []*counters{r.fingerprint, r.kind, r._package, r.function}
+}
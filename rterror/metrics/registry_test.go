@@ -0,0 +1,138 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/metrics"
+)
+
+func TestRegistry(test *testing.T) {
+	registry := metrics.New().Register()
+	defer registry.Unregister()
+
+	err := rterror.New("error")
+	rterror.New("other error").SetKind("ignored")
+
+	snapshot := registry.Snapshot()
+
+	assert.Equal(test, uint64(2), snapshot.Total)
+	assert.Equal(test, map[string]uint64{"": 2}, snapshot.Kind)
+	assert.Equal(test, map[string]uint64{"gitlab.com/tymonx/go-error/rterror/metrics_test": 2}, snapshot.Package)
+	assert.Equal(test, map[string]uint64{"gitlab.com/tymonx/go-error/rterror/metrics_test.TestRegistry": 2}, snapshot.Function)
+	assert.Len(test, snapshot.Fingerprint, 2)
+	assert.Contains(test, snapshot.Fingerprint, err.Fingerprint())
+}
+
+func TestRegistryKind(test *testing.T) {
+	defer rterror.OnCreate(func(r *rterror.RuntimeError) {
+		r.SetKind("internal")
+	})()
+
+	registry := metrics.New().Register()
+	defer registry.Unregister()
+
+	rterror.New("error")
+
+	assert.Equal(test, map[string]uint64{"internal": 1}, registry.Snapshot().Kind)
+}
+
+func TestRegistryReport(test *testing.T) {
+	registry := metrics.New()
+
+	registry.Report(fmt.Errorf("context: %w", rterror.New("error").SetKind("not_found")))
+	registry.Report(rterror.New("error").SetKind("internal"))
+	registry.Report(errors.New("error"))
+	registry.Report(nil)
+
+	snapshot := registry.Snapshot()
+
+	assert.Equal(test, uint64(2), snapshot.Total)
+	assert.Equal(test, map[string]uint64{"not_found": 1, "internal": 1}, snapshot.Kind)
+}
+
+func TestRegistryUnregister(test *testing.T) {
+	registry := metrics.New().Register().Unregister()
+
+	rterror.New("error")
+
+	assert.Zero(test, registry.Total())
+}
+
+func TestRegistryLimit(test *testing.T) {
+	registry := metrics.New().SetLimit(2)
+
+	for _, message := range []string{"A", "B", "C", "D"} {
+		registry.Observe(rterror.New(message))
+	}
+
+	snapshot := registry.Snapshot()
+
+	assert.Equal(test, 2, registry.GetLimit())
+	assert.Len(test, snapshot.Fingerprint, 3)
+	assert.Equal(test, uint64(2), snapshot.Fingerprint[metrics.OverflowLabel])
+}
+
+func TestRegistryReset(test *testing.T) {
+	registry := metrics.New()
+
+	registry.Observe(rterror.New("error"))
+	registry.Reset()
+
+	assert.Zero(test, registry.Total())
+	assert.Empty(test, registry.Snapshot().Fingerprint)
+}
+
+func TestRegistryConcurrent(test *testing.T) {
+	registry := metrics.New().SetLimit(4)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				registry.Observe(rterror.New("error").SetKind(rterror.Kind(rune('a' + i))))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(test, uint64(800), registry.Total())
+	assert.Equal(test, uint64(800), registry.Snapshot().Function["gitlab.com/tymonx/go-error/rterror/metrics_test.TestRegistryConcurrent.func1"])
+}
+
+func TestRegistryPublish(test *testing.T) {
+	registry := metrics.New().Publish("rterror_test")
+
+	registry.Observe(rterror.New("error"))
+
+	var snapshot metrics.Snapshot
+
+	assert.NoError(test, json.Unmarshal([]byte(expvar.Get("rterror_test").String()), &snapshot))
+	assert.Equal(test, uint64(1), snapshot.Total)
+}
@@ -0,0 +1,19 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oteltrace adapts the otelx package to the trace.Span interface from
+// the OpenTelemetry API. It is a separate module, so the go-error module
+// doesn't depend on OpenTelemetry. Until the otelx package is released, the
+// go-error module is replaced with the enclosing module in the go.mod file.
+package oteltrace
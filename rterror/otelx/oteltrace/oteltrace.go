@@ -0,0 +1,81 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oteltrace
+
+import (
+	"fmt"
+
+	"gitlab.com/tymonx/go-error/rterror/otelx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recorder defines a recorder that adds span events to span.
+type Recorder struct {
+	span    trace.Span
+	options []trace.EventOption
+}
+
+// NewRecorder creates a new recorder object that adds span events to span with provided event options.
+func NewRecorder(span trace.Span, options ...trace.EventOption) *Recorder {
+	return &Recorder{
+		span:    span,
+		options: options,
+	}
+}
+
+// RecordError records error as exception span event like the span.RecordError()
+// method, but with attributes from the otelx package. Nothing is recorded
+// for nil error or if span is not recording.
+func RecordError(span trace.Span, err error, options ...trace.EventOption) {
+	if (err == nil) || !span.IsRecording() {
+		return
+	}
+
+	otelx.Record(NewRecorder(span, options...), err)
+}
+
+// Attributes returns attributes of error converted to OpenTelemetry attributes.
+func Attributes(err error) []attribute.KeyValue {
+	return convert(otelx.Attributes(err))
+}
+
+// AddEvent adds span event with attributes to span.
+func (r *Recorder) AddEvent(name string, attributes []otelx.Attribute) {
+	options := append([]trace.EventOption{trace.WithAttributes(convert(attributes)...)}, r.options...)
+
+	r.span.AddEvent(name, options...)
+}
+
+func convert(attributes []otelx.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, 0, len(attributes))
+
+	for _, a := range attributes {
+		key := attribute.Key(a.Key)
+
+		switch value := a.Value.(type) {
+		case string:
+			converted = append(converted, key.String(value))
+		case int:
+			converted = append(converted, key.Int(value))
+		case bool:
+			converted = append(converted, key.Bool(value))
+		default:
+			converted = append(converted, key.String(fmt.Sprint(value)))
+		}
+	}
+
+	return converted
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oteltrace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/otelx"
+	"gitlab.com/tymonx/go-error/rterror/otelx/oteltrace"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecordError(test *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := provider.Tracer("test").Start(context.Background(), "span")
+
+	err := rterror.New("cannot query {p0}", "users").SetKind("unavailable").Wrap(errors.New("refused"))
+
+	oteltrace.RecordError(span, err, trace.WithAttributes(attribute.String("custom", "value")))
+	oteltrace.RecordError(span, nil)
+	span.End()
+
+	spans := recorder.Ended()
+
+	assert.Len(test, spans, 1)
+	assert.Len(test, spans[0].Events(), 1)
+
+	event := spans[0].Events()[0]
+	attributes := attribute.NewSet(event.Attributes...)
+
+	value, _ := attributes.Value(otelx.AttributeExceptionMessage)
+	lineno, _ := attributes.Value(otelx.AttributeCodeLineno)
+	kind, _ := attributes.Value(otelx.AttributeKind)
+	custom, _ := attributes.Value("custom")
+
+	assert.Equal(test, otelx.EventName, event.Name)
+	assert.Equal(test, "cannot query users: refused", value.AsString())
+	assert.Equal(test, int64(err.Line()), lineno.AsInt64())
+	assert.Equal(test, "unavailable", kind.AsString())
+	assert.Equal(test, "value", custom.AsString())
+}
+
+func TestRecordErrorNotRecording(test *testing.T) {
+	span := trace.SpanFromContext(context.Background())
+
+	assert.NotPanics(test, func() {
+		oteltrace.RecordError(span, rterror.New("error"))
+	})
+}
+
+func TestAttributes(test *testing.T) {
+	err := rterror.New("error")
+
+	assert.Contains(test, oteltrace.Attributes(err), attribute.Int(otelx.AttributeCodeLineno, err.Line()))
+	assert.Contains(test, oteltrace.Attributes(err), attribute.String(otelx.AttributeCodeFunction, "TestAttributes"))
+	assert.Empty(test, oteltrace.Attributes(nil))
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelx
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// These constants define names of span event and attributes from the
+// OpenTelemetry exception and code semantic conventions.
+const (
+	EventName                    = "exception"
+	AttributeExceptionType       = "exception.type"
+	AttributeExceptionMessage    = "exception.message"
+	AttributeExceptionStacktrace = "exception.stacktrace"
+	AttributeCodeFunction        = "code.function"
+	AttributeCodeNamespace       = "code.namespace"
+	AttributeCodeFilepath        = "code.filepath"
+	AttributeCodeLineno          = "code.lineno"
+	AttributeKind                = "rterror.kind"
+	AttributeFingerprint         = "rterror.fingerprint"
+)
+
+// Attribute defines an attribute with key and value. Value is a string,
+// an int or a bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Event defines a span event with name and attributes.
+type Event struct {
+	Name       string
+	Attributes []Attribute
+}
+
+// Recorder defines an interface that adds event with attributes to span.
+type Recorder interface {
+	AddEvent(name string, attributes []Attribute)
+}
+
+// Attributes returns attributes of error. The exception.type attribute is the
+// error type, the exception.message attribute contains error messages of error
+// chain in one line and the exception.stacktrace attribute contains error chain
+// printed with the %+v verb. The code attributes and runtime error kind and
+// fingerprint are taken from the outermost runtime error in error chain.
+// It returns nil for nil error.
+func Attributes(err error) []Attribute {
+	if err == nil {
+		return nil
+	}
+
+	attributes := []Attribute{
+		{Key: AttributeExceptionType, Value: fmt.Sprintf("%T", err)},
+		{Key: AttributeExceptionMessage, Value: rterror.Summary(err)},
+		{Key: AttributeExceptionStacktrace, Value: fmt.Sprintf("%+v", err)},
+	}
+
+	var r *rterror.RuntimeError
+
+	if !errors.As(err, &r) {
+		return attributes
+	}
+
+	attributes = append(attributes,
+		Attribute{Key: AttributeCodeFunction, Value: r.FunctionBase()},
+		Attribute{Key: AttributeCodeNamespace, Value: r.Package()},
+		Attribute{Key: AttributeCodeFilepath, Value: r.File()},
+		Attribute{Key: AttributeCodeLineno, Value: r.Line()},
+		Attribute{Key: AttributeFingerprint, Value: r.Fingerprint()},
+	)
+
+	if kind := r.GetKind(); kind != "" {
+		attributes = append(attributes, Attribute{Key: AttributeKind, Value: string(kind)})
+	}
+
+	return attributes
+}
+
+// NewEvent returns exception span event with attributes of error.
+func NewEvent(err error) Event {
+	return Event{
+		Name:       EventName,
+		Attributes: Attributes(err),
+	}
+}
+
+// Record adds exception span event with attributes of error to recorder.
+// Nothing is recorded for nil error.
+func Record(recorder Recorder, err error) {
+	if err == nil {
+		return
+	}
+
+	event := NewEvent(err)
+
+	recorder.AddEvent(event.Name, event.Attributes)
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelx_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/otelx"
+)
+
+type recorder struct {
+	events []otelx.Event
+}
+
+func (r *recorder) AddEvent(name string, attributes []otelx.Attribute) {
+	r.events = append(r.events, otelx.Event{Name: name, Attributes: attributes})
+}
+
+func TestAttributes(test *testing.T) {
+	inner := rterror.New("cannot query {p0}", "users").SetKind("unavailable").Wrap(errors.New("refused"))
+	err := fmt.Errorf("handler: %w", inner)
+
+	assert.Equal(test, []otelx.Attribute{
+		{Key: otelx.AttributeExceptionType, Value: "*fmt.wrapError"},
+		{Key: otelx.AttributeExceptionMessage, Value: "handler: " + inner.Error()},
+		{Key: otelx.AttributeExceptionStacktrace, Value: "handler: " + inner.Error()},
+		{Key: otelx.AttributeCodeFunction, Value: "TestAttributes"},
+		{Key: otelx.AttributeCodeNamespace, Value: "gitlab.com/tymonx/go-error/rterror/otelx_test"},
+		{Key: otelx.AttributeCodeFilepath, Value: inner.File()},
+		{Key: otelx.AttributeCodeLineno, Value: inner.Line()},
+		{Key: otelx.AttributeFingerprint, Value: inner.Fingerprint()},
+		{Key: otelx.AttributeKind, Value: "unavailable"},
+	}, otelx.Attributes(err))
+
+	assert.Nil(test, otelx.Attributes(nil))
+	assert.Len(test, otelx.Attributes(errors.New("error")), 3)
+}
+
+func TestAttributesRuntimeError(test *testing.T) {
+	err := rterror.New("cannot get user").Wrap(rterror.New("cannot query {p0}", "users").Wrap(errors.New("refused")))
+	attributes := otelx.Attributes(err)
+
+	assert.Equal(test, otelx.Attribute{Key: otelx.AttributeExceptionType, Value: "*rterror.RuntimeError"}, attributes[0])
+	assert.Equal(test, otelx.Attribute{Key: otelx.AttributeExceptionMessage, Value: "cannot get user: cannot query users: refused"}, attributes[1])
+	assert.Equal(test, otelx.Attribute{Key: otelx.AttributeExceptionStacktrace, Value: fmt.Sprintf("%+v", err)}, attributes[2])
+	assert.Equal(test, otelx.Attribute{Key: otelx.AttributeCodeLineno, Value: err.Line()}, attributes[6])
+	assert.Len(test, attributes, 8)
+}
+
+func TestRecord(test *testing.T) {
+	r := &recorder{}
+	err := rterror.New("error")
+
+	otelx.Record(r, err)
+	otelx.Record(r, nil)
+
+	assert.Equal(test, []otelx.Event{otelx.NewEvent(err)}, r.events)
+	assert.Equal(test, otelx.EventName, r.events[0].Name)
+}
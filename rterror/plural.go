@@ -0,0 +1,141 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"strings"
+)
+
+// PluralForm defines a plural form category like defined by the Unicode CLDR.
+type PluralForm string
+
+// These constants define supported plural forms.
+const (
+	PluralZero  PluralForm = "zero"
+	PluralOne   PluralForm = "one"
+	PluralTwo   PluralForm = "two"
+	PluralFew   PluralForm = "few"
+	PluralMany  PluralForm = "many"
+	PluralOther PluralForm = "other"
+)
+
+// PluralRule defines a function that returns plural form for provided count.
+type PluralRule func(count float64) PluralForm
+
+// PluralRuleOf returns built-in plural rule for provided language. It returns
+// English plural rule with the one and other forms for unknown languages.
+func PluralRuleOf(language string) PluralRule {
+	switch baseLanguage(language) {
+	case "ja", "ko", "zh", "vi", "th", "id":
+		return pluralOther
+	case "fr", "pt":
+		return pluralFrench
+	case "pl":
+		return pluralPolish
+	case "ru", "uk", "be":
+		return pluralEastSlavic
+	case "cs", "sk":
+		return pluralCzech
+	default:
+		return pluralEnglish
+	}
+}
+
+func pluralOther(float64) PluralForm {
+	return PluralOther
+}
+
+func pluralEnglish(count float64) PluralForm {
+	if count == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+func pluralFrench(count float64) PluralForm {
+	if (count >= 0) && (count < 2) {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+func pluralPolish(count float64) PluralForm {
+	n, ok := integer(count)
+
+	switch {
+	case !ok:
+		return PluralOther
+	case n == 1:
+		return PluralOne
+	case (n%10 >= 2) && (n%10 <= 4) && ((n%100 < 12) || (n%100 > 14)):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralEastSlavic(count float64) PluralForm {
+	n, ok := integer(count)
+
+	switch {
+	case !ok:
+		return PluralOther
+	case (n%10 == 1) && (n%100 != 11):
+		return PluralOne
+	case (n%10 >= 2) && (n%10 <= 4) && ((n%100 < 12) || (n%100 > 14)):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+func pluralCzech(count float64) PluralForm {
+	n, ok := integer(count)
+
+	switch {
+	case !ok:
+		return PluralMany
+	case n == 1:
+		return PluralOne
+	case (n >= 2) && (n <= 4):
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+// integer returns absolute integer value of count and true if count has no fraction part.
+func integer(count float64) (int64, bool) {
+	if count < 0 {
+		count = -count
+	}
+
+	n := int64(count)
+
+	return n, float64(n) == count
+}
+
+// baseLanguage returns lowercase language subtag from language tag like en-US or pt_BR.
+func baseLanguage(language string) string {
+	language = strings.ToLower(language)
+
+	if index := strings.IndexAny(language, "-_"); index != -1 {
+		language = language[:index]
+	}
+
+	return language
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"sync"
+)
+
+// DefaultPublicMessage defines a generic public message used when error chain
+// has no public message and there is no public message for error kind.
+const DefaultPublicMessage = "Internal error"
+
+// SanitizedError defines an opaque error safe to return across trust boundaries.
+// It contains only public message, kind and identifier of sanitized error.
+// It doesn't wrap sanitized error, but it matches its kind with the errors.Is() function.
+type SanitizedError struct {
+	message string
+	kind    Kind
+	id      string
+}
+
+var (
+	gPublicMutex    sync.RWMutex        // nolint: gochecknoglobals
+	gPublicMessages = map[Kind]string{} // nolint: gochecknoglobals
+)
+
+// SetPublicKindMessage sets package-wide generic public message for provided
+// error kind. It is used by the PublicMessage() function when error chain has no public message.
+func SetPublicKindMessage(kind Kind, message string) {
+	gPublicMutex.Lock()
+	defer gPublicMutex.Unlock()
+
+	gPublicMessages[kind] = message
+}
+
+// GetPublicKindMessage returns package-wide generic public message for provided
+// error kind. It returns DefaultPublicMessage if it was not set.
+func GetPublicKindMessage(kind Kind) string {
+	gPublicMutex.RLock()
+	defer gPublicMutex.RUnlock()
+
+	if message, ok := gPublicMessages[kind]; ok {
+		return message
+	}
+
+	return DefaultPublicMessage
+}
+
+// ResetPublicKindMessages removes all package-wide generic public messages.
+func ResetPublicKindMessages() {
+	gPublicMutex.Lock()
+	defer gPublicMutex.Unlock()
+
+	gPublicMessages = map[Kind]string{}
+}
+
+// PublicMessage returns the outermost public message from error chain formatted
+// with its public arguments. Without it, it returns generic public message
+// for kind of the outermost runtime error with kind. It never returns internal error message.
+func PublicMessage(err error) string {
+	var kind Kind
+
+	for _, r := range RuntimeErrors(err) {
+		if r.publicMessage != "" {
+			return r.PublicString()
+		}
+
+		if kind == "" {
+			kind = r.kind
+		}
+	}
+
+	return GetPublicKindMessage(kind)
+}
+
+// Sanitize returns an opaque error safe to return across trust boundaries.
+// Its error message is equal to the PublicMessage() result. It preserves
+// kind and identifier of the outermost runtime error with kind. It returns nil for nil error.
+func Sanitize(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	sanitized := &SanitizedError{
+		message: PublicMessage(err),
+	}
+
+	for _, r := range RuntimeErrors(err) {
+		if sanitized.id == "" {
+			sanitized.id = r.id
+		}
+
+		if r.kind != "" {
+			sanitized.kind = r.kind
+			break
+		}
+	}
+
+	return sanitized
+}
+
+// SetPublicMessage sets public message with its own public arguments. Public
+// message is safe to show to users and it is formatted like error message.
+func (r *RuntimeError) SetPublicMessage(message string, arguments ...interface{}) *RuntimeError {
+	r.publicMessage = message
+	r.publicArguments = arguments
+
+	return r
+}
+
+// GetPublicMessage returns unformatted public message.
+func (r *RuntimeError) GetPublicMessage() string {
+	return r.publicMessage
+}
+
+// GetPublicArguments returns public arguments.
+func (r *RuntimeError) GetPublicArguments() []interface{} {
+	return r.publicArguments
+}
+
+// ResetPublicMessage removes public message and public arguments.
+func (r *RuntimeError) ResetPublicMessage() *RuntimeError {
+	r.publicMessage = ""
+	r.publicArguments = nil
+
+	return r
+}
+
+// PublicString returns public message formatted with public arguments. It
+// returns generic public message for error kind if public message was not set
+// or if it cannot be formatted. Format failure is handled according to failure
+// mode, but internal error message is never returned, even in debug mode.
+func (r *RuntimeError) PublicString() string {
+	if r.publicMessage == "" {
+		return GetPublicKindMessage(r.kind)
+	}
+
+	formatted, err := r.messageFormatter().Format(r.publicMessage, r.publicArguments...)
+
+	if err != nil {
+		r.reportFailure(r.publicMessage, err)
+		return GetPublicKindMessage(r.kind)
+	}
+
+	return formatted
+}
+
+// Is returns true if target is runtime error kind equal to runtime error kind.
+// It allows matching runtime errors by kind with the errors.Is() function.
+func (r *RuntimeError) Is(target error) bool {
+	kind, ok := target.(Kind)
+
+	return ok && (kind != "") && (kind == r.kind)
+}
+
+// Error returns runtime error kind. It allows using kind as target of the errors.Is() function.
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Error returns public message.
+func (s *SanitizedError) Error() string {
+	return s.message
+}
+
+// Kind returns kind of sanitized error.
+func (s *SanitizedError) Kind() Kind {
+	return s.kind
+}
+
+// ID returns unique identifier of sanitized error. It can be used to correlate
+// sanitized error with logged internal error.
+func (s *SanitizedError) ID() string {
+	return s.id
+}
+
+// Is returns true if target is kind equal to kind of sanitized error.
+func (s *SanitizedError) Is(target error) bool {
+	kind, ok := target.(Kind)
+
+	return ok && (kind != "") && (kind == s.kind)
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func TestPublicMessage(test *testing.T) {
+	defer rterror.ResetPublicKindMessages()
+
+	rterror.SetPublicKindMessage("not_found", "Not found")
+
+	internal := rterror.New("cannot query table {p0}", "users").SetKind("internal")
+	notFound := rterror.New("user {p0} not found in {p1}", 7, "db").SetKind("not_found").Wrap(internal)
+	public := rterror.New("cannot get user").SetPublicMessage("User {p0} doesn't exist", 7).Wrap(notFound)
+
+	assert.Equal(test, "User 7 doesn't exist", rterror.PublicMessage(rterror.New("handler").Wrap(public)))
+	assert.Equal(test, "User {p0} doesn't exist", public.GetPublicMessage())
+	assert.Equal(test, []interface{}{7}, public.GetPublicArguments())
+	assert.Equal(test, "Not found", rterror.PublicMessage(notFound))
+	assert.Equal(test, "Not found", rterror.PublicMessage(fmt.Errorf("wrapped: %w", notFound)))
+	assert.Equal(test, rterror.DefaultPublicMessage, rterror.PublicMessage(internal))
+	assert.Equal(test, rterror.DefaultPublicMessage, rterror.PublicMessage(errors.New("error")))
+	assert.Equal(test, rterror.DefaultPublicMessage, public.ResetPublicMessage().PublicString())
+	assert.Equal(test, "Not found", notFound.PublicString())
+	assert.Equal(test, rterror.DefaultPublicMessage, rterror.GetPublicKindMessage("unknown"))
+}
+
+func TestPublicMessageJSON(test *testing.T) {
+	data, err := rterror.New("internal").SetPublicMessage("Public {p0}", 1).MarshalJSON()
+
+	assert.NoError(test, err)
+	assert.Contains(test, string(data), `"public":"Public 1"`)
+}
+
+func TestKindIs(test *testing.T) {
+	err := fmt.Errorf("wrapped: %w", rterror.New("error").SetKind("not_found"))
+
+	assert.True(test, errors.Is(err, rterror.Kind("not_found")))
+	assert.False(test, errors.Is(err, rterror.Kind("internal")))
+	assert.False(test, errors.Is(rterror.New("error"), rterror.Kind("")))
+	assert.Equal(test, "not_found", rterror.Kind("not_found").Error())
+}
+
+func TestSanitize(test *testing.T) {
+	setCapture(test)
+
+	cause := rterror.New("password {p0} rejected by {p1}", "secret", "ldap").SetKind("unauthorized")
+	err := rterror.New("login failed").SetPublicMessage("Invalid credentials").Wrap(cause)
+
+	sanitized := rterror.Sanitize(err)
+
+	var s *rterror.SanitizedError
+
+	assert.Equal(test, "Invalid credentials", sanitized.Error())
+	assert.NotContains(test, fmt.Sprintf("%+v", sanitized), "secret")
+	assert.True(test, errors.Is(sanitized, rterror.Kind("unauthorized")))
+	assert.False(test, errors.Is(sanitized, cause))
+	assert.Nil(test, errors.Unwrap(sanitized))
+	assert.True(test, errors.As(sanitized, &s))
+	assert.Equal(test, rterror.Kind("unauthorized"), s.Kind())
+	assert.Equal(test, err.ID(), s.ID())
+	assert.Nil(test, rterror.Sanitize(nil))
+	assert.Equal(test, rterror.DefaultPublicMessage, rterror.Sanitize(errors.New("error")).Error())
+}
+
+func TestPublicMessageFormatFailure(test *testing.T) {
+	var reported []*rterror.FormatError
+
+	defer rterror.ResetFailureMode()
+	defer rterror.DisableDebug()
+	defer rterror.ResetPublicKindMessages()
+	defer rterror.OnFormatFailure(func(r *rterror.RuntimeError, err *rterror.FormatError) {
+		reported = append(reported, err)
+	})()
+
+	rterror.SetFailureMode(rterror.FailureReport)
+	rterror.EnableDebug()
+	rterror.SetPublicKindMessage("unauthorized", "Unauthorized")
+
+	err := rterror.New("db password for {p0} rejected", "admin").SetPublicMessage("Cannot log in {p1}", "x")
+
+	assert.Equal(test, rterror.DefaultPublicMessage, err.PublicString())
+	assert.Equal(test, rterror.DefaultPublicMessage, rterror.PublicMessage(err))
+	assert.Equal(test, rterror.DefaultPublicMessage, rterror.Sanitize(err).Error())
+	assert.Equal(test, "Unauthorized", rterror.Sanitize(err.SetKind("unauthorized")).Error())
+	assert.Len(test, reported, 4)
+	assert.Equal(test, "Cannot log in {p1}", reported[0].Format)
+}
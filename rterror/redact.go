@@ -0,0 +1,307 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+// RedactedValue defines a placeholder that replaces redacted values.
+const RedactedValue = "[REDACTED]"
+
+// Secret defines a wrapper of sensitive error argument or field value. It is
+// always printed and serialized as RedactedValue when redaction is enabled.
+// Wrapped value is available only with the Value() method.
+type Secret struct {
+	value interface{}
+}
+
+type redactedKeysHolder struct {
+	keys []string
+}
+
+var (
+	gRedaction           = boolToInt32(true)     // nolint: gochecknoglobals
+	gRedactedKeys        atomic.Value            // nolint: gochecknoglobals
+	gDefaultRedactedKeys = DefaultRedactedKeys() // nolint: gochecknoglobals
+)
+
+// DefaultRedactedKeys returns default field keys that are redacted.
+func DefaultRedactedKeys() []string {
+	return []string{
+		"password",
+		"passwd",
+		"secret",
+		"token",
+		"api_key",
+		"apikey",
+		"authorization",
+		"cookie",
+		"credential",
+		"private_key",
+	}
+}
+
+// NewSecret creates a new secret object that wraps sensitive value.
+func NewSecret(value interface{}) Secret {
+	return Secret{
+		value: value,
+	}
+}
+
+// Value returns wrapped unredacted value. It must be used only for trusted local debugging.
+func (s Secret) Value() interface{} {
+	return s.value
+}
+
+// String returns RedactedValue or wrapped value formatted with the %v verb when redaction is disabled.
+func (s Secret) String() string {
+	if IsRedactionEnabled() {
+		return RedactedValue
+	}
+
+	return fmt.Sprint(s.value)
+}
+
+// GoString returns the same value as the String() method.
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// Format implements the fmt.Formatter interface. It redacts value for all format verbs.
+func (s Secret) Format(state fmt.State, verb rune) {
+	if IsRedactionEnabled() {
+		_, _ = state.Write([]byte(RedactedValue))
+		return
+	}
+
+	_, _ = fmt.Fprintf(state, fmt.FormatString(state, verb), s.value)
+}
+
+// MarshalText encodes secret to text.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// SetRedaction enables or disables package-wide redaction policy. It applies
+// to secret values and to fields and named arguments with redacted keys in all
+// renderers and serializers. Redaction is enabled by default.
+func SetRedaction(enabled bool) {
+	atomic.StoreInt32(&gRedaction, boolToInt32(enabled))
+}
+
+// EnableRedaction enables redaction.
+func EnableRedaction() {
+	SetRedaction(true)
+}
+
+// DisableRedaction disables redaction. It must be used only for trusted local debugging.
+func DisableRedaction() {
+	SetRedaction(false)
+}
+
+// IsRedactionEnabled returns true if redaction is enabled. Otherwise, it returns false.
+func IsRedactionEnabled() bool {
+	return atomic.LoadInt32(&gRedaction) != 0
+}
+
+// SetRedactedKeys sets package-wide field keys that are redacted. Field key is
+// redacted if it contains any of redacted keys, ignoring case.
+func SetRedactedKeys(keys ...string) {
+	lowered := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		lowered = append(lowered, strings.ToLower(key))
+	}
+
+	gRedactedKeys.Store(redactedKeysHolder{
+		keys: lowered,
+	})
+}
+
+// GetRedactedKeys returns package-wide field keys that are redacted.
+func GetRedactedKeys() []string {
+	if holder, ok := gRedactedKeys.Load().(redactedKeysHolder); ok {
+		return append([]string(nil), holder.keys...)
+	}
+
+	return DefaultRedactedKeys()
+}
+
+// ResetRedactedKeys resets package-wide field keys that are redacted to DefaultRedactedKeys().
+func ResetRedactedKeys() {
+	SetRedactedKeys(DefaultRedactedKeys()...)
+}
+
+// IsRedactedKey returns true if field with provided key is redacted. Otherwise, it returns false.
+func IsRedactedKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, redacted := range redactedKeys() {
+		if strings.Contains(key, redacted) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UnredactedArguments returns error arguments with secret values unwrapped.
+// It must be used only for trusted local debugging.
+func (r *RuntimeError) UnredactedArguments() []interface{} {
+	arguments := make([]interface{}, len(r._arguments))
+
+	for index, argument := range r._arguments {
+		arguments[index] = unredact(argument)
+	}
+
+	return arguments
+}
+
+// UnredactedField returns runtime error field value with secret value unwrapped.
+// It must be used only for trusted local debugging.
+func (r *RuntimeError) UnredactedField(key string) interface{} {
+	return unredact(r.fields[key])
+}
+
+// UnredactedFields returns a copy of runtime error fields with secret values
+// unwrapped. It must be used only for trusted local debugging.
+func (r *RuntimeError) UnredactedFields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(r.fields))
+
+	for key, value := range r.fields {
+		fields[key] = unredact(value)
+	}
+
+	return fields
+}
+
+// UnredactedString returns error message string formatted with unredacted
+// error arguments. It must be used only for trusted local debugging.
+func (r *RuntimeError) UnredactedString() string {
+	formatted, err := r.messageFormatter().Format(r._message, r.UnredactedArguments()...)
+
+	if err != nil {
+		return r.failback(r._message, err)
+	}
+
+	return formatted
+}
+
+// redactedKeys returns package-wide field keys that are redacted without copying them.
+func redactedKeys() []string {
+	if holder, ok := gRedactedKeys.Load().(redactedKeysHolder); ok {
+		return holder.keys
+	}
+
+	return gDefaultRedactedKeys
+}
+
+// redact returns RedactedValue for fields with redacted keys when redaction is enabled.
+func redact(key string, value interface{}) interface{} {
+	if IsRedactionEnabled() && IsRedactedKey(key) {
+		return RedactedValue
+	}
+
+	return value
+}
+
+// redactFields returns fields with redacted values or nil if there are no fields.
+func redactFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	redacted := make(map[string]interface{}, len(fields))
+
+	for key, value := range fields {
+		redacted[key] = redact(key, value)
+	}
+
+	return redacted
+}
+
+// redactArguments returns error arguments with values of redacted keys in
+// named arguments replaced with RedactedValue when redaction is enabled.
+// Named arguments are formatter.Named and map[string]interface{} maps.
+// Arguments are copied only if any value is redacted.
+func redactArguments(arguments []interface{}) []interface{} {
+	if !IsRedactionEnabled() {
+		return arguments
+	}
+
+	redacted, copied := arguments, false
+
+	for index, argument := range arguments {
+		var named map[string]interface{}
+
+		switch value := argument.(type) {
+		case formatter.Named:
+			named = value
+		case map[string]interface{}:
+			named = value
+		default:
+			continue
+		}
+
+		fields, ok := redactNamed(named)
+
+		if !ok {
+			continue
+		}
+
+		if !copied {
+			redacted, copied = append([]interface{}(nil), arguments...), true
+		}
+
+		if _, ok := argument.(formatter.Named); ok {
+			redacted[index] = formatter.Named(fields)
+		} else {
+			redacted[index] = fields
+		}
+	}
+
+	return redacted
+}
+
+// redactNamed returns a copy of named arguments with redacted values and true
+// if any key is redacted. Otherwise, it returns nil and false.
+func redactNamed(named map[string]interface{}) (map[string]interface{}, bool) {
+	for key := range named {
+		if IsRedactedKey(key) {
+			fields := make(map[string]interface{}, len(named))
+
+			for key, value := range named {
+				fields[key] = redact(key, value)
+			}
+
+			return fields, true
+		}
+	}
+
+	return nil, false
+}
+
+func unredact(value interface{}) interface{} {
+	if secret, ok := value.(Secret); ok {
+		return secret.value
+	}
+
+	return value
+}
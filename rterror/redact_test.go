@@ -0,0 +1,126 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+func TestSecret(test *testing.T) {
+	secret := rterror.NewSecret("s3cr3t")
+
+	assert.Equal(test, "s3cr3t", secret.Value())
+	assert.Equal(test, rterror.RedactedValue, secret.String())
+	assert.Equal(test, "[REDACTED] [REDACTED] [REDACTED]", fmt.Sprintf("%v %q %#v", secret, secret, secret))
+}
+
+func TestRedactArguments(test *testing.T) {
+	err := rterror.New("token {p0} for {user}", rterror.NewSecret("s3cr3t"), formatter.Named{"user": "bob"})
+
+	data, e := err.MarshalJSON()
+
+	assert.NoError(test, e)
+	assert.Equal(test, "token [REDACTED] for bob", err.String())
+	assert.NotContains(test, err.Error(), "s3cr3t")
+	assert.NotContains(test, fmt.Sprintf("%+v", err), "s3cr3t")
+	assert.NotContains(test, string(data), "s3cr3t")
+	assert.Contains(test, string(data), `"arguments":["[REDACTED]",{"user":"bob"}]`)
+	assert.Equal(test, "token s3cr3t for bob", err.UnredactedString())
+	assert.Equal(test, "s3cr3t", err.UnredactedArguments()[0])
+	assert.Equal(test, "[REDACTED]", rterror.New("%d", rterror.NewSecret(1)).SetFormatter(rterror.SprintfFormatter{}).String())
+}
+
+func TestRedactNamedArguments(test *testing.T) {
+	err := rterror.New("login {user} with {token} and {api_key}", map[string]interface{}{
+		"user":  "bob",
+		"token": "s3cr3t",
+	}, formatter.Named{"api_key": "k3y"})
+
+	data, e := err.MarshalJSON()
+
+	assert.NoError(test, e)
+	assert.Equal(test, "login bob with [REDACTED] and [REDACTED]", err.String())
+	assert.NotContains(test, err.Error(), "s3cr3t")
+	assert.NotContains(test, string(data), "s3cr3t")
+	assert.NotContains(test, string(data), "k3y")
+	assert.Contains(test, string(data), `"arguments":[{"token":"[REDACTED]","user":"bob"},{"api_key":"[REDACTED]"}]`)
+	assert.Equal(test, "login bob with s3cr3t and k3y", err.UnredactedString())
+	assert.Equal(test, "s3cr3t", err.Arguments()[0].(map[string]interface{})["token"])
+}
+
+func TestRedactFields(test *testing.T) {
+	err := rterror.New("error").SetFields(map[string]interface{}{
+		"user":          "bob",
+		"DB_Password":   "pass",
+		"Authorization": "Bearer abc",
+		"session":       rterror.NewSecret("xyz"),
+	})
+
+	var decoded struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+
+	data, e := err.MarshalJSON()
+
+	assert.NoError(test, e)
+	assert.NoError(test, json.Unmarshal(data, &decoded))
+	assert.Equal(test, map[string]interface{}{
+		"user":          "bob",
+		"DB_Password":   rterror.RedactedValue,
+		"Authorization": rterror.RedactedValue,
+		"session":       rterror.RedactedValue,
+	}, decoded.Fields)
+	assert.Equal(test, "bob", err.GetField("user"))
+	assert.Equal(test, rterror.RedactedValue, err.GetField("DB_Password"))
+	assert.Nil(test, err.GetField("missing"))
+	assert.Equal(test, map[string]interface{}{
+		"user":          "bob",
+		"DB_Password":   rterror.RedactedValue,
+		"Authorization": rterror.RedactedValue,
+		"session":       rterror.NewSecret("xyz"),
+	}, err.GetFields())
+	assert.Equal(test, "pass", err.UnredactedField("DB_Password"))
+	assert.Equal(test, "xyz", err.UnredactedFields()["session"])
+}
+
+func TestRedactionPolicy(test *testing.T) {
+	defer rterror.EnableRedaction()
+	defer rterror.ResetRedactedKeys()
+
+	rterror.SetRedactedKeys("PIN")
+
+	err := rterror.New("{p0}", rterror.NewSecret(1234)).SetField("pin", 1234).SetField("password", "pass")
+
+	assert.Equal(test, []string{"pin"}, rterror.GetRedactedKeys())
+	assert.True(test, rterror.IsRedactedKey("card_pin"))
+	assert.Equal(test, rterror.RedactedValue, err.GetField("pin"))
+	assert.Equal(test, "pass", err.GetField("password"))
+
+	rterror.DisableRedaction()
+
+	assert.False(test, rterror.IsRedactionEnabled())
+	assert.Equal(test, "1234", err.String())
+	assert.Equal(test, 1234, err.GetField("pin"))
+
+	rterror.ResetRedactedKeys()
+
+	assert.Equal(test, rterror.DefaultRedactedKeys(), rterror.GetRedactedKeys())
+}
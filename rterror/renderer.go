@@ -0,0 +1,385 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// These constants define default values used by chain renderer.
+const (
+	DefaultSeparator = ": "
+)
+
+// Style defines a style of rendered error chain.
+type Style int
+
+// These constants define built-in styles of rendered error chain.
+const (
+	// StyleTree renders each error in new line, wrapped errors are indented:
+	//
+	//  <error>
+	//  `--<error>
+	//     `--<error>
+	StyleTree Style = iota
+
+	// StyleOneLine renders all errors in single line joined with separator:
+	//
+	//  <error>: <error>: <error>
+	StyleOneLine
+
+	// StyleReverse renders each error in new line starting from the root error.
+	// Indention levels are reversed, the deepest wrapped errors are not indented:
+	//
+	//  <root error>
+	//  `--<error>
+	//     `--<error>
+	StyleReverse
+
+	// StyleNumbered renders each error in new line as numbered list:
+	//
+	//  1. <error>
+	//  2. <error>
+	//  3. <error>
+	StyleNumbered
+)
+
+// Renderer defines an interface that renders runtime error with all its wrapped errors.
+type Renderer interface {
+	Render(writer io.Writer, r *RuntimeError) error
+}
+
+// ChainRenderer defines a built-in renderer of error chains.
+type ChainRenderer struct {
+	style     Style
+	maxDepth  int
+	collapse  bool
+	indent    string
+	separator string
+}
+
+// link defines an error from rendered error chain with its indention level.
+type link struct {
+	err     error
+	message string
+	depth   int
+	omitted int
+}
+
+type rendererHolder struct {
+	renderer Renderer
+}
+
+var (
+	gRenderer        atomic.Value             // nolint: gochecknoglobals
+	gDefaultRenderer = NewRenderer(StyleTree) // nolint: gochecknoglobals
+)
+
+// NewRenderer creates a new chain renderer object with provided style.
+func NewRenderer(style Style) *ChainRenderer {
+	return &ChainRenderer{
+		style:     style,
+		indent:    DefaultIndent,
+		separator: DefaultSeparator,
+	}
+}
+
+// SetRenderer sets package-wide renderer used by runtime errors without own renderer.
+func SetRenderer(renderer Renderer) {
+	gRenderer.Store(rendererHolder{
+		renderer: renderer,
+	})
+}
+
+// GetRenderer returns package-wide renderer. Default tree style renderer is
+// shared by all runtime errors, use the NewRenderer() function to customize it.
+func GetRenderer() Renderer {
+	if holder, ok := gRenderer.Load().(rendererHolder); ok && (holder.renderer != nil) {
+		return holder.renderer
+	}
+
+	return gDefaultRenderer
+}
+
+// ResetRenderer resets package-wide renderer to default value that is tree style.
+func ResetRenderer() {
+	SetRenderer(nil)
+}
+
+// SetStyle sets style of rendered error chain.
+func (c *ChainRenderer) SetStyle(style Style) *ChainRenderer {
+	c.style = style
+	return c
+}
+
+// GetStyle returns style of rendered error chain.
+func (c *ChainRenderer) GetStyle() Style {
+	return c.style
+}
+
+// SetMaxDepth sets maximum number of rendered errors. Remaining errors are
+// replaced with the "... N more" marker. Zero means no limit.
+func (c *ChainRenderer) SetMaxDepth(maxDepth int) *ChainRenderer {
+	c.maxDepth = maxDepth
+	return c
+}
+
+// GetMaxDepth returns maximum number of rendered errors.
+func (c *ChainRenderer) GetMaxDepth() int {
+	return c.maxDepth
+}
+
+// SetCollapse enables or disables collapsing of consecutive duplicate error messages.
+func (c *ChainRenderer) SetCollapse(collapse bool) *ChainRenderer {
+	c.collapse = collapse
+	return c
+}
+
+// EnableCollapse enables collapsing of consecutive duplicate error messages.
+func (c *ChainRenderer) EnableCollapse() *ChainRenderer {
+	return c.SetCollapse(true)
+}
+
+// DisableCollapse disables collapsing of consecutive duplicate error messages.
+func (c *ChainRenderer) DisableCollapse() *ChainRenderer {
+	return c.SetCollapse(false)
+}
+
+// IsCollapseEnabled returns true if collapsing of consecutive duplicate error
+// messages is enabled. Otherwise, it returns false.
+func (c *ChainRenderer) IsCollapseEnabled() bool {
+	return c.collapse
+}
+
+// SetIndent sets indent glyph used by tree styles.
+func (c *ChainRenderer) SetIndent(indent string) *ChainRenderer {
+	c.indent = indent
+	return c
+}
+
+// GetIndent returns indent glyph used by tree styles.
+func (c *ChainRenderer) GetIndent() string {
+	return c.indent
+}
+
+// SetSeparator sets separator used by the one line style.
+func (c *ChainRenderer) SetSeparator(separator string) *ChainRenderer {
+	c.separator = separator
+	return c
+}
+
+// GetSeparator returns separator used by the one line style.
+func (c *ChainRenderer) GetSeparator() string {
+	return c.separator
+}
+
+// Render renders runtime error with all its wrapped errors to writer. Errors
+// that wrap multiple errors, like errors created with the errors.Join() function,
+// are rendered as branches of wrapped errors with the same indention.
+func (c *ChainRenderer) Render(writer io.Writer, r *RuntimeError) error {
+	chain := unwrapChain(r)
+
+	if (c.maxDepth > 0) && (len(chain) > c.maxDepth) {
+		omitted := 0
+
+		for _, l := range chain[c.maxDepth:] {
+			omitted += l.count()
+		}
+
+		chain = append(chain[:c.maxDepth:c.maxDepth], link{
+			message: moreMarker(omitted),
+			depth:   chain[c.maxDepth].depth,
+		})
+	}
+
+	if c.style == StyleReverse {
+		chain = reverse(chain)
+	}
+
+	var (
+		previous  string
+		collapsed []int
+	)
+
+	index := 0
+
+	for _, l := range chain {
+		// Errors below collapsed error are moved up
+		for (len(collapsed) != 0) && (collapsed[len(collapsed)-1] >= l.depth) {
+			collapsed = collapsed[:len(collapsed)-1]
+		}
+
+		message := l.text()
+
+		if c.collapse && (index != 0) && (message == previous) {
+			collapsed = append(collapsed, l.depth)
+			continue
+		}
+
+		if err := c.write(writer, index, l.depth-len(collapsed), message); err != nil {
+			return err
+		}
+
+		previous = message
+		index++
+	}
+
+	return nil
+}
+
+func (c *ChainRenderer) write(writer io.Writer, index, level int, message string) (err error) {
+	switch c.style {
+	case StyleOneLine:
+		if index != 0 {
+			_, err = io.WriteString(writer, c.separator)
+		}
+	case StyleNumbered:
+		if index != 0 {
+			_, err = io.WriteString(writer, "\n")
+		}
+
+		if err == nil {
+			_, err = io.WriteString(writer, strconv.Itoa(index+1)+". ")
+		}
+	case StyleTree, StyleReverse:
+		fallthrough
+	default:
+		if index != 0 {
+			err = c.writeIndent(writer, level)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(writer, message)
+
+	return err
+}
+
+// writeIndent writes new line and indention without allocating whole indention string.
+func (c *ChainRenderer) writeIndent(writer io.Writer, level int) error {
+	const spaces = "                                "
+
+	if _, err := io.WriteString(writer, "\n"); (err != nil) || (level == 0) {
+		return err
+	}
+
+	for indent := utf8.RuneCountInString(c.indent) * (level - 1); indent > 0; indent -= len(spaces) {
+		chunk := spaces
+
+		if indent < len(chunk) {
+			chunk = chunk[:indent]
+		}
+
+		if _, err := io.WriteString(writer, chunk); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(writer, c.indent)
+
+	return err
+}
+
+// unwrapChain returns runtime error and all its wrapped errors with their
+// indention levels. Errors that wrap multiple errors are replaced by their
+// wrapped errors, each of them starts new branch with the same indention level.
+// Errors above MaxWalkDepth are replaced by the "... N more" marker, at most
+// MaxWalkDepth of them are counted.
+func unwrapChain(r *RuntimeError) []link {
+	chain := []link{{err: r}}
+	marker := link{}
+
+	var walk func(err error, depth int)
+
+	walk = func(err error, depth int) {
+		for ; (err != nil) && (marker.omitted < MaxWalkDepth); err = errors.Unwrap(err) {
+			if multi, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range multi.Unwrap() {
+					walk(e, depth)
+				}
+
+				return
+			}
+
+			if len(chain) < MaxWalkDepth {
+				chain = append(chain, link{err: err, depth: depth})
+			} else if marker.omitted++; marker.omitted == 1 {
+				marker.depth = depth
+			}
+
+			depth++
+		}
+	}
+
+	walk(r.err, 1)
+
+	if marker.omitted > 0 {
+		marker.message = moreMarker(marker.omitted)
+		chain = append(chain, marker)
+	}
+
+	return chain
+}
+
+// reverse returns chain in reversed order with reversed indention levels.
+func reverse(chain []link) []link {
+	reversed := make([]link, 0, len(chain))
+	last := 0
+
+	for _, l := range chain {
+		if l.depth > last {
+			last = l.depth
+		}
+	}
+
+	for index := len(chain) - 1; index >= 0; index-- {
+		l := chain[index]
+		l.depth = last - l.depth
+		reversed = append(reversed, l)
+	}
+
+	return reversed
+}
+
+func (l *link) text() string {
+	switch err := l.err.(type) {
+	case nil:
+		return l.message
+	case *RuntimeError:
+		return err.TopError()
+	default:
+		return err.Error()
+	}
+}
+
+// count returns number of errors represented by link.
+func (l *link) count() int {
+	if l.omitted > 0 {
+		return l.omitted
+	}
+
+	return 1
+}
+
+func moreMarker(count int) string {
+	return "... " + strconv.Itoa(count) + " more"
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func newChain(messages ...string) *rterror.RuntimeError {
+	var err error
+
+	for index := len(messages) - 1; index > 0; index-- {
+		if err == nil {
+			err = errors.New(messages[index])
+		} else {
+			err = rterror.New(messages[index]).SetFormat("{.String}").Wrap(err)
+		}
+	}
+
+	return rterror.New(messages[0]).SetFormat("{.String}").Wrap(err)
+}
+
+func TestRendererTree(test *testing.T) {
+	assert.Equal(test, "A\n`--B\n   `--C\n      `--D", newChain("A", "B", "C", "D").Error())
+}
+
+func TestRendererTreeIndent(test *testing.T) {
+	renderer := rterror.NewRenderer(rterror.StyleTree).SetIndent("└─ ")
+
+	assert.Equal(test, "└─ ", renderer.GetIndent())
+	assert.Equal(test, "A\n└─ B\n   └─ C", newChain("A", "B", "C").SetRenderer(renderer).Error())
+}
+
+func TestRendererOneLine(test *testing.T) {
+	renderer := rterror.NewRenderer(rterror.StyleOneLine)
+
+	assert.Equal(test, rterror.StyleOneLine, renderer.GetStyle())
+	assert.Equal(test, "A: B: C", newChain("A", "B", "C").SetRenderer(renderer).Error())
+	assert.Equal(test, "A | B | C", newChain("A", "B", "C").SetRenderer(renderer.SetSeparator(" | ")).Error())
+	assert.Equal(test, " | ", renderer.GetSeparator())
+}
+
+func TestRendererReverse(test *testing.T) {
+	renderer := rterror.NewRenderer(rterror.StyleReverse)
+
+	assert.Equal(test, "C\n`--B\n   `--A", newChain("A", "B", "C").SetRenderer(renderer).Error())
+}
+
+func TestRendererNumbered(test *testing.T) {
+	renderer := rterror.NewRenderer(rterror.StyleNumbered)
+
+	assert.Equal(test, "1. A\n2. B\n3. C", newChain("A", "B", "C").SetRenderer(renderer).Error())
+}
+
+func TestRendererMaxDepth(test *testing.T) {
+	renderer := rterror.NewRenderer(rterror.StyleOneLine).SetMaxDepth(2)
+	err := newChain("A", "B", "C", "D").SetRenderer(renderer)
+
+	assert.Equal(test, 2, renderer.GetMaxDepth())
+	assert.Equal(test, "A: B: ... 2 more", err.Error())
+	assert.Equal(test, "... 2 more\n`--B\n   `--A", err.SetRenderer(renderer.SetStyle(rterror.StyleReverse)).Error())
+	assert.Equal(test, "A: B", newChain("A", "B").SetRenderer(renderer.SetStyle(rterror.StyleOneLine)).Error())
+}
+
+func TestRendererMaxWalkDepth(test *testing.T) {
+	cycle := &cycleError{}
+	cycle.next = cycle
+
+	err := rterror.New("A").SetFormat("{.String}").Wrap(cycle)
+	lines := strings.Split(err.SetRenderer(rterror.NewRenderer(rterror.StyleNumbered)).Error(), "\n")
+
+	assert.Len(test, lines, rterror.MaxWalkDepth+1)
+	assert.Equal(test, "1025. ... 1024 more", lines[rterror.MaxWalkDepth])
+	assert.Equal(test, "A: cycle: ... 2046 more",
+		err.SetRenderer(rterror.NewRenderer(rterror.StyleOneLine).SetMaxDepth(2)).Error())
+}
+
+func TestRendererCollapse(test *testing.T) {
+	renderer := rterror.NewRenderer(rterror.StyleOneLine).EnableCollapse()
+	err := newChain("A", "A", "B", "B", "B", "A").SetRenderer(renderer)
+
+	assert.True(test, renderer.IsCollapseEnabled())
+	assert.Equal(test, "A: B: A", err.Error())
+	assert.Equal(test, "A: A: B: B: B: A", err.SetRenderer(renderer.DisableCollapse()).Error())
+}
+
+func TestRendererJoined(test *testing.T) {
+	joined := errors.Join(newChain("B", "C"), errors.Join(errors.New("D"), errors.New("E")))
+	err := rterror.New("A").SetFormat("{.String}").Wrap(joined)
+
+	assert.Equal(test, "A\n`--B\n   `--C\n`--D\n`--E", err.Error())
+	assert.Equal(test, "E\n`--D\nC\n`--B\n   `--A",
+		err.SetRenderer(rterror.NewRenderer(rterror.StyleReverse)).Error())
+	assert.Equal(test, "A: B: ... 3 more",
+		err.SetRenderer(rterror.NewRenderer(rterror.StyleOneLine).SetMaxDepth(2)).Error())
+}
+
+func TestSetRenderer(test *testing.T) {
+	defer rterror.ResetRenderer()
+
+	rterror.SetRenderer(rterror.NewRenderer(rterror.StyleOneLine))
+
+	err := newChain("A", "B")
+
+	assert.Equal(test, "A: B", err.Error())
+	assert.Equal(test, "A\n`--B", err.SetRenderer(rterror.NewRenderer(rterror.StyleTree)).Error())
+	assert.Equal(test, "A: B", err.ResetRenderer().Error())
+
+	rterror.ResetRenderer()
+
+	assert.Equal(test, "A\n`--B", err.Error())
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterrortest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// AssertOrigin asserts that the first runtime error from error chain was
+// created in function. Function is a full function name like returned by
+// the Function() method or a function name without package path like returned
+// by the FunctionBase() method, for example "(*Store).Get" or "TestStore.func1".
+func AssertOrigin(t testing.TB, err error, function string) bool {
+	t.Helper()
+
+	r, ok := runtimeError(t, err)
+
+	if !ok {
+		return false
+	}
+
+	if (r.Function() == function) || (r.FunctionBase() == function) {
+		return true
+	}
+
+	return assert.Equal(t, function, r.FunctionBase(), "runtime error origin")
+}
+
+// AssertMessage asserts that the first runtime error from error chain has
+// unformatted message and arguments. Arguments are compared using the
+// assert.ObjectsAreEqual() function.
+func AssertMessage(t testing.TB, err error, message string, arguments ...interface{}) bool {
+	t.Helper()
+
+	r, ok := runtimeError(t, err)
+
+	if !ok {
+		return false
+	}
+
+	if arguments == nil {
+		arguments = []interface{}{}
+	}
+
+	actual := r.Arguments()
+
+	if actual == nil {
+		actual = []interface{}{}
+	}
+
+	return assert.Equal(t, message, r.Message(), "runtime error message") &&
+		assert.Equal(t, arguments, actual, "runtime error arguments")
+}
+
+// AssertKind asserts that the first runtime error from error chain has kind.
+func AssertKind(t testing.TB, err error, kind rterror.Kind) bool {
+	t.Helper()
+
+	r, ok := runtimeError(t, err)
+
+	if !ok {
+		return false
+	}
+
+	return assert.Equal(t, kind, r.GetKind(), "runtime error kind")
+}
+
+// AssertFields asserts that the first runtime error from error chain has all
+// provided fields. Other fields of runtime error are ignored.
+func AssertFields(t testing.TB, err error, fields map[string]interface{}) bool {
+	t.Helper()
+
+	r, ok := runtimeError(t, err)
+
+	if !ok {
+		return false
+	}
+
+	actual := make(map[string]interface{}, len(fields))
+	all := r.GetFields()
+
+	for key := range fields {
+		if value, ok := all[key]; ok {
+			actual[key] = value
+		}
+	}
+
+	return assert.Equal(t, fields, actual, "runtime error fields")
+}
+
+func runtimeError(t testing.TB, err error) (*rterror.RuntimeError, bool) {
+	t.Helper()
+
+	var r *rterror.RuntimeError
+
+	if !errors.As(err, &r) {
+		return nil, assert.Fail(t, "Error chain does not contain runtime error", "error: %#v", err)
+	}
+
+	return r, true
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterrortest_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/rterrortest"
+)
+
+type recorder struct {
+	testing.TB
+	helpers  int
+	messages []string
+}
+
+func newRecorder(test *testing.T) *recorder {
+	return &recorder{
+		TB: test,
+	}
+}
+
+func (r *recorder) Helper() {
+	r.helpers++
+}
+
+func (r *recorder) Errorf(format string, arguments ...interface{}) {
+	r.messages = append(r.messages, fmt.Sprintf(format, arguments...))
+}
+
+type store struct{}
+
+func (*store) Get(key string) error {
+	return rterror.New("key {p0} not found", key).SetKind("not_found").SetField("key", key).Wrap(os.ErrNotExist)
+}
+
+func TestAssertOrigin(test *testing.T) {
+	err := new(store).Get("a")
+
+	assert.True(test, rterrortest.AssertOrigin(test, err, "(*store).Get"))
+	assert.True(test, rterrortest.AssertOrigin(test, err, "gitlab.com/tymonx/go-error/rterror/rterrortest_test.(*store).Get"))
+
+	r := newRecorder(test)
+
+	assert.False(test, rterrortest.AssertOrigin(r, err, "store.Get"))
+	assert.Len(test, r.messages, 1)
+	assert.Contains(test, r.messages[0], "runtime error origin")
+	assert.NotZero(test, r.helpers)
+}
+
+func TestAssertMessage(test *testing.T) {
+	err := new(store).Get("a")
+
+	assert.True(test, rterrortest.AssertMessage(test, err, "key {p0} not found", "a"))
+	assert.True(test, rterrortest.AssertMessage(test, rterror.New("error"), "error"))
+
+	r := newRecorder(test)
+
+	assert.False(test, rterrortest.AssertMessage(r, err, "key {p0} not found", "b"))
+	assert.Contains(test, r.messages[0], "runtime error arguments")
+}
+
+func TestAssertKind(test *testing.T) {
+	err := new(store).Get("a")
+
+	assert.True(test, rterrortest.AssertKind(test, err, "not_found"))
+
+	r := newRecorder(test)
+
+	assert.False(test, rterrortest.AssertKind(r, err, "internal"))
+	assert.False(test, rterrortest.AssertKind(r, os.ErrNotExist, "internal"))
+	assert.Len(test, r.messages, 2)
+	assert.Contains(test, r.messages[1], "Error chain does not contain runtime error")
+}
+
+func TestAssertFields(test *testing.T) {
+	err := new(store).Get("a").(*rterror.RuntimeError).SetField("other", 1)
+
+	assert.True(test, rterrortest.AssertFields(test, err, map[string]interface{}{"key": "a"}))
+
+	r := newRecorder(test)
+
+	assert.False(test, rterrortest.AssertFields(r, err, map[string]interface{}{"key": "b", "missing": 1}))
+	assert.Contains(test, r.messages[0], "runtime error fields")
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterrortest
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// Matcher defines a matcher of single error from error chain. The String()
+// method returns description of expected error used in reported diff.
+type Matcher interface {
+	Match(err error) bool
+	String() string
+}
+
+type matcher struct {
+	match       func(err error) bool
+	description string
+}
+
+// runtimeObject defines methods of runtime error. It is implemented by runtime
+// error and by custom error types that embed runtime error.
+type runtimeObject interface {
+	error
+	Message() string
+	Function() string
+	FunctionBase() string
+	GetKind() rterror.Kind
+}
+
+// NewMatcher creates a new matcher from match function and description.
+func NewMatcher(description string, match func(err error) bool) Matcher {
+	return &matcher{
+		match:       match,
+		description: description,
+	}
+}
+
+// Message returns matcher that matches runtime error with unformatted message
+// or other error with message returned by the Error() method.
+func Message(message string) Matcher {
+	return NewMatcher("message "+strconv.Quote(message), func(err error) bool {
+		if r, ok := err.(runtimeObject); ok {
+			return r.Message() == message
+		}
+
+		return err.Error() == message
+	})
+}
+
+// Origin returns matcher that matches runtime error created in function. Function
+// is a full function name or a function name without package path.
+func Origin(function string) Matcher {
+	return NewMatcher("origin "+function+"()", func(err error) bool {
+		r, ok := err.(runtimeObject)
+		return ok && ((r.Function() == function) || (r.FunctionBase() == function))
+	})
+}
+
+// Kind returns matcher that matches runtime error with kind.
+func Kind(kind rterror.Kind) Matcher {
+	return NewMatcher("kind "+strconv.Quote(string(kind)), func(err error) bool {
+		r, ok := err.(runtimeObject)
+		return ok && (r.GetKind() == kind)
+	})
+}
+
+// Is returns matcher that matches error equal to target error or error that
+// reports it is equivalent to target with the Is() method. Errors wrapped by
+// matched error are not checked.
+func Is(target error) Matcher {
+	return NewMatcher(fmt.Sprintf("is %T: %v", target, target), func(err error) bool {
+		if err == target { // nolint: errorlint
+			return true
+		}
+
+		if is, ok := err.(interface{ Is(error) bool }); ok {
+			return is.Is(target)
+		}
+
+		return false
+	})
+}
+
+// Type returns matcher that matches error of type T.
+func Type[T error]() Matcher {
+	return NewMatcher("type "+reflect.TypeOf((*T)(nil)).Elem().String(), func(err error) bool {
+		_, ok := err.(T) // nolint: errorlint
+		return ok
+	})
+}
+
+// Any returns matcher that matches any error.
+func Any() Matcher {
+	return NewMatcher("any", func(err error) bool {
+		return true
+	})
+}
+
+// All returns matcher that matches error matched by all provided matchers.
+func All(matchers ...Matcher) Matcher {
+	descriptions := make([]string, 0, len(matchers))
+
+	for _, m := range matchers {
+		descriptions = append(descriptions, m.String())
+	}
+
+	return NewMatcher(strings.Join(descriptions, ", "), func(err error) bool {
+		for _, m := range matchers {
+			if !m.Match(err) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// AssertChain asserts that error chain returned by the rterror.Chain()
+// function has the same length as provided matchers and that each error
+// is matched by matcher at the same position. On failure, it reports diff
+// between expected and actual error chain, where matched errors are equal.
+func AssertChain(t testing.TB, err error, matchers ...Matcher) bool {
+	t.Helper()
+
+	chain := rterror.Chain(err)
+	matched := len(chain) == len(matchers)
+	expected := make([]string, len(matchers))
+	actual := make([]string, len(chain))
+
+	for index, e := range chain {
+		actual[index] = describe(e)
+	}
+
+	for index, m := range matchers {
+		if (index < len(chain)) && m.Match(chain[index]) {
+			expected[index] = actual[index]
+		} else {
+			expected[index] = m.String()
+			matched = false
+		}
+	}
+
+	if matched {
+		return true
+	}
+
+	return assert.Equal(t, expected, actual, "error chain")
+}
+
+// Match returns true if matcher matches error.
+func (m *matcher) Match(err error) bool {
+	return m.match(err)
+}
+
+// String returns matcher description.
+func (m *matcher) String() string {
+	return m.description
+}
+
+// describe returns description of error from error chain used in reported diff.
+func describe(err error) string {
+	r, ok := err.(runtimeObject) // nolint: errorlint
+
+	if !ok {
+		return fmt.Sprintf("%T: %v", err, err)
+	}
+
+	description := r.FunctionBase() + "(): " + r.Message()
+
+	if kind := r.GetKind(); kind != "" {
+		description += " [" + string(kind) + "]"
+	}
+
+	return description
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterrortest_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/rterrortest"
+)
+
+func TestAssertChain(test *testing.T) {
+	err := rterror.New("cannot load").Wrap(new(store).Get("a"))
+
+	assert.True(test, rterrortest.AssertChain(test, err,
+		rterrortest.All(rterrortest.Message("cannot load"), rterrortest.Origin("TestAssertChain")),
+		rterrortest.All(rterrortest.Kind("not_found"), rterrortest.Origin("(*store).Get")),
+		rterrortest.Is(fs.ErrNotExist),
+	))
+
+	assert.True(test, rterrortest.AssertChain(test, &os.PathError{Op: "open", Path: "a", Err: fs.ErrNotExist},
+		rterrortest.Type[*os.PathError](),
+		rterrortest.Message("file does not exist"),
+	))
+
+	assert.True(test, rterrortest.AssertChain(test, errors.Join(err, os.ErrClosed),
+		rterrortest.Any(),
+		rterrortest.Message("cannot load"),
+		rterrortest.Any(),
+		rterrortest.Any(),
+		rterrortest.Is(os.ErrClosed),
+	))
+}
+
+func TestAssertChainDiff(test *testing.T) {
+	err := rterror.New("cannot load").Wrap(new(store).Get("a"))
+	r := newRecorder(test)
+
+	assert.False(test, rterrortest.AssertChain(r, err,
+		rterrortest.Message("cannot load"),
+		rterrortest.Kind("internal"),
+	))
+
+	assert.Len(test, r.messages, 1)
+	assert.Contains(test, r.messages[0], `- (string) (len=15) "kind \"internal\""`)
+	assert.Contains(test, r.messages[0], `+ (string) (len=46) "(*store).Get(): key {p0} not found [not_found]",`)
+	assert.Contains(test, r.messages[0], `+ (string) (len=40) "*errors.errorString: file does not exist"`)
+	assert.Contains(test, r.messages[0], `  (string) (len=34) "TestAssertChainDiff(): cannot load",`)
+}
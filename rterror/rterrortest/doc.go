@@ -0,0 +1,29 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rterrortest implements test helpers for runtime errors. Helpers
+// check error origin, message, kind, fields and error chain without hardcoded
+// line numbers and file paths. On failure, they report readable diff between
+// expected and actual values. All helpers call the t.Helper() method, so failures
+// are reported at the line of test that called helper. Example:
+//
+//  err := store.Get("key")
+//
+//  rterrortest.AssertOrigin(t, err, "(*Store).Get")
+//  rterrortest.AssertMessage(t, err, "key {p0} not found", "key")
+//  rterrortest.AssertChain(t, err,
+//      rterrortest.Message("key {p0} not found"),
+//      rterrortest.Is(os.ErrNotExist),
+//  )
+package rterrortest
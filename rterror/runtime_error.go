@@ -16,11 +16,10 @@ package rterror
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"gitlab.com/tymonx/go-formatter/formatter"
 )
@@ -31,8 +30,6 @@ const (
 	DefaultIndent = "`--"
 	DefaultFormat = `{cyan | bright}{.Package}{reset}:{bold}{cyan}{.FileBase}{reset}:{bold}{magenta}{.Line}{reset}:` +
 		`{bold}{blue | bright}{.FunctionBase}(){reset}: {.String}`
-
-	indentSize = len(DefaultIndent)
 )
 
 // RuntimeError defines a runtime error with message string formatted using
@@ -40,14 +37,25 @@ const (
 // the Go Formatter library. It contains line number, file path and function name
 // from where a runtime error was called.
 type RuntimeError struct {
-	pc         [1]uintptr
-	_message   string
-	format     string
-	formatter  *formatter.Formatter
-	_arguments []interface{}
-	err        error
+	pc              [1]uintptr
+	_message        string
+	format          string
+	formatter       MessageFormatter
+	_arguments      []interface{}
+	err             error
+	kind            Kind
+	fields          map[string]interface{}
+	time            time.Time
+	id              string
+	renderer        Renderer
+	publicMessage   string
+	publicArguments []interface{}
+	templateID      string
 }
 
+// Kind defines a runtime error kind used to classify runtime errors.
+type Kind string
+
 // New creates a new runtime error object with message string formatted using
 // "replacement fields" surrounded by curly braces {} format strings, line number,
 // file path and function name from where the New() function was called.
@@ -61,16 +69,11 @@ func New(message string, arguments ...interface{}) *RuntimeError {
 // The argument skip is the number of stack frames to ascend,
 // with 0 identifying the caller of NewSkipCaller.
 func NewSkipCaller(skip int, message string, arguments ...interface{}) *RuntimeError {
-	r := &RuntimeError{
-		format:     DefaultFormat,
-		formatter:  formatter.New(),
-		_message:   message,
-		_arguments: arguments,
-	}
+	var pc [1]uintptr
 
-	runtime.Callers((SkipCall + SkipCall + skip), r.pc[:])
+	runtime.Callers((SkipCall + SkipCall + skip), pc[:])
 
-	return r
+	return newRuntimeError(pc, message, arguments...)
 }
 
 // Message returns unformatted error message.
@@ -105,17 +108,7 @@ func (r *RuntimeError) Function() string {
 
 // FunctionBase returns function base name.
 func (r *RuntimeError) FunctionBase() string {
-	function := r.Function()
-
-	if index := strings.LastIndexByte(function, '/'); index != -1 {
-		function = function[index+1:]
-	}
-
-	if index := strings.IndexByte(function, '.'); index != -1 {
-		function = function[index+1:]
-	}
-
-	return function
+	return functionBase(r.Function())
 }
 
 // Package returns full package path.
@@ -154,24 +147,121 @@ func (r *RuntimeError) ResetFormat() *RuntimeError {
 	return r
 }
 
-// SetFormatter sets formatter.
-func (r *RuntimeError) SetFormatter(f *formatter.Formatter) *RuntimeError {
+// SetFormatter sets message formatter used to format error message.
+// It overrides package-wide message formatter.
+func (r *RuntimeError) SetFormatter(f MessageFormatter) *RuntimeError {
 	r.formatter = f
 	return r
 }
 
-// GetFormatter returns formatter.
-func (r *RuntimeError) GetFormatter() *formatter.Formatter {
-	return r.formatter
+// GetFormatter returns message formatter used to format error message. It
+// returns the MessageFormatter interface instead of the *formatter.Formatter
+// type, use type assertion to get the formatter object from the Go Formatter
+// library. Without own message formatter, it returns package-wide message
+// formatter that is shared by all runtime errors. Use the SetFormatter()
+// method with a new formatter object to modify it only for runtime error.
+func (r *RuntimeError) GetFormatter() MessageFormatter {
+	return r.messageFormatter()
+}
+
+// ResetFormatter resets message formatter to package-wide message formatter.
+func (r *RuntimeError) ResetFormatter() *RuntimeError {
+	r.formatter = nil
+	return r
+}
+
+// messageFormatter returns own or package-wide message formatter.
+func (r *RuntimeError) messageFormatter() MessageFormatter {
+	if r.formatter != nil {
+		return r.formatter
+	}
+
+	return GetFormatter()
+}
+
+// SetKind sets runtime error kind.
+func (r *RuntimeError) SetKind(kind Kind) *RuntimeError {
+	r.kind = kind
+	return r
+}
+
+// GetKind returns runtime error kind.
+func (r *RuntimeError) GetKind() Kind {
+	return r.kind
+}
+
+// SetField sets runtime error field with provided key and value.
+func (r *RuntimeError) SetField(key string, value interface{}) *RuntimeError {
+	if r.fields == nil {
+		r.fields = make(map[string]interface{})
+	}
+
+	r.fields[key] = value
+
+	return r
+}
+
+// SetFields sets runtime error fields with provided keys and values.
+func (r *RuntimeError) SetFields(fields map[string]interface{}) *RuntimeError {
+	for key, value := range fields {
+		r.SetField(key, value)
+	}
+
+	return r
+}
+
+// GetField returns runtime error field value. It returns nil if field doesn't exist.
+// Value of field with redacted key is replaced with RedactedValue.
+func (r *RuntimeError) GetField(key string) interface{} {
+	if value, ok := r.fields[key]; ok {
+		return redact(key, value)
+	}
+
+	return nil
+}
+
+// GetFields returns a copy of runtime error fields. Values of fields with
+// redacted keys are replaced with RedactedValue.
+func (r *RuntimeError) GetFields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(r.fields))
+
+	for key, value := range r.fields {
+		fields[key] = redact(key, value)
+	}
+
+	return fields
+}
+
+// SetRenderer sets renderer used by the Error() method. It overrides package-wide renderer.
+func (r *RuntimeError) SetRenderer(renderer Renderer) *RuntimeError {
+	r.renderer = renderer
+	return r
+}
+
+// GetRenderer returns renderer used by the Error() method.
+func (r *RuntimeError) GetRenderer() Renderer {
+	if r.renderer != nil {
+		return r.renderer
+	}
+
+	return GetRenderer()
+}
+
+// ResetRenderer resets renderer to package-wide renderer.
+func (r *RuntimeError) ResetRenderer() *RuntimeError {
+	r.renderer = nil
+	return r
 }
 
 // String returns formatted error message string.
 func (r *RuntimeError) String() string {
-	if formatted, err := r.formatter.Format(r._message, r._arguments...); err == nil {
-		return formatted
+	formatted, err := r.messageFormatter().Format(r._message, redactArguments(r._arguments)...)
+
+	if err != nil {
+		return r.failback(r._message, err)
 	}
 
-	return r._message // Failback
+	return formatted
 }
 
 // MarshalText encodes runtime error to text.
@@ -181,18 +271,26 @@ func (r *RuntimeError) MarshalText() ([]byte, error) {
 
 // MarshalJSON encodes runtime error to JSON.
 func (r *RuntimeError) MarshalJSON() ([]byte, error) {
+	l := r.locator()
+
 	return json.Marshal(&marshal{
-		Line:      r.Line(),
-		File:      r.File(),
+		Line:      l.Line(),
+		File:      l.File(),
 		Function:  r.Function(),
 		Message:   r._message,
-		Arguments: r._arguments,
+		Arguments: redactArguments(r._arguments),
+		Public:    r.marshalPublic(),
+		Kind:      r.kind,
+		Fields:    redactFields(r.fields),
+		Time:      marshalTime(l),
+		ID:        l.ID(),
 	})
 }
 
-// Error returns formatted error message string.
+// Error returns formatted error message string. It is rendered using
+// runtime error renderer or package-wide renderer.
 //
-// With wrapped errors it returns:
+// With wrapped errors and default renderer it returns:
 //
 //  <error>
 //  `--<error>
@@ -201,29 +299,7 @@ func (r *RuntimeError) MarshalJSON() ([]byte, error) {
 func (r *RuntimeError) Error() (result string) {
 	var builder strings.Builder
 
-	fmt.Fprint(&builder, r.TopError())
-
-	for level, err := 0, r.err; err != nil; level, err = (level + 1), errors.Unwrap(err) {
-		var message string
-
-		if e, ok := err.(*RuntimeError); ok {
-			message = e.TopError()
-		} else {
-			message = err.Error()
-		}
-
-		indent := indentSize * level
-
-		builder.Grow(1 + indent + indentSize + len(message))
-
-		fmt.Fprintln(&builder)
-
-		for i := 0; i < indent; i++ {
-			fmt.Fprint(&builder, " ") // indention
-		}
-
-		fmt.Fprint(&builder, DefaultIndent, message)
-	}
+	_ = r.GetRenderer().Render(&builder, r)
 
 	return builder.String()
 }
@@ -234,16 +310,21 @@ func (r *RuntimeError) Error() (result string) {
 //
 //  <error>
 func (r *RuntimeError) TopError() string {
-	if formatted, err := formatter.Format(r.format, r); err == nil {
-		return formatted
+	formatted, err := formatter.Format(r.format, r.locator())
+
+	if err != nil {
+		return r.failback(r.format, err)
 	}
 
-	return r._message // Failback
+	return formatted
 }
 
 // Wrap wraps provided error into runtime error.
 func (r *RuntimeError) Wrap(err error) *RuntimeError {
 	r.err = err
+
+	invokeHooks(&gWrapHooks, r)
+
 	return r
 }
 
@@ -252,7 +333,35 @@ func (r *RuntimeError) Unwrap() error {
 	return r.err
 }
 
+// newRuntimeError creates a new runtime error object with provided program counter.
+func newRuntimeError(pc [1]uintptr, message string, arguments ...interface{}) *RuntimeError {
+	r := &RuntimeError{
+		pc:         pc,
+		format:     DefaultFormat,
+		_message:   message,
+		_arguments: arguments,
+	}
+
+	r.capture()
+	invokeHooks(&gCreateHooks, r)
+
+	return r
+}
+
 func (r *RuntimeError) frame() *runtime.Frame {
 	frame, _ := runtime.CallersFrames(r.pc[:]).Next()
 	return &frame
 }
+
+// functionBase returns function base name from full function name.
+func functionBase(function string) string {
+	if index := strings.LastIndexByte(function, '/'); index != -1 {
+		function = function[index+1:]
+	}
+
+	if index := strings.IndexByte(function, '.'); index != -1 {
+		function = function[index+1:]
+	}
+
+	return function
+}
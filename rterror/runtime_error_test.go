@@ -77,6 +77,20 @@ func TestRuntimeErrorGetFormatter(test *testing.T) {
 	assert.Equal(test, want, rterror.New("").SetFormatter(want).GetFormatter())
 }
 
+func TestRuntimeErrorGetFormatterDefault(test *testing.T) {
+	err := rterror.New("Error {p0}", 1).SetFormat("{.String}")
+
+	f, ok := err.GetFormatter().(*formatter.Formatter)
+
+	assert.True(test, ok)
+	assert.Same(test, rterror.GetFormatter(), f)
+
+	rterror.SetFormatter(rterror.SprintfFormatter{})
+	defer rterror.ResetFormatter()
+
+	assert.Equal(test, rterror.SprintfFormatter{}, err.GetFormatter())
+}
+
 func TestRuntimeErrorUnwrap(test *testing.T) {
 	err := rterror.New("error")
 
@@ -88,12 +102,16 @@ func TestRuntimeErrorUnwrapNil(test *testing.T) {
 }
 
 func TestRuntimeErrorMessageFailback(test *testing.T) {
+	setFallback(test)
+
 	want := "Error message {invalid}"
 
 	assert.Equal(test, want, rterror.New(want, 3, "foo").String())
 }
 
 func TestRuntimeErrorFailback(test *testing.T) {
+	setFallback(test)
+
 	want := "Error message {p1} {p0}"
 
 	assert.Equal(test, want, rterror.New(want, 3, "foo").SetFormat("{invalid}").Error())
@@ -152,3 +170,21 @@ func TestRuntimeErrorStructPackage(test *testing.T) {
 func TestRuntimeErrorStructPackageBase(test *testing.T) {
 	assert.Equal(test, "rterror_test", new(Struct).Error().PackageBase())
 }
+
+func TestRuntimeErrorKind(test *testing.T) {
+	assert.Equal(test, rterror.Kind("not_found"), rterror.New("error").SetKind("not_found").GetKind())
+}
+
+func TestRuntimeErrorFields(test *testing.T) {
+	err := rterror.New("error").SetField("a", 1).SetFields(map[string]interface{}{"b": "2"})
+
+	assert.Equal(test, 1, err.GetField("a"))
+	assert.Nil(test, err.GetField("c"))
+	assert.Equal(test, map[string]interface{}{"a": 1, "b": "2"}, err.GetFields())
+}
+
+func TestRuntimeErrorFieldsFormat(test *testing.T) {
+	err := rterror.New("error").SetField("user", "foo").SetFormat(`{.GetKind}:{.GetField "user"}: {.String}`).SetKind("auth")
+
+	assert.Equal(test, "auth:foo: error", err.Error())
+}
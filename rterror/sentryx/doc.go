@@ -0,0 +1,26 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sentryx implements exporter that converts runtime error chains into
+// Sentry events. Each error from error chain becomes exception value with stack
+// frames, runtime error fields become tags and runtime error fingerprints
+// become event fingerprint. Events are sent as Sentry envelopes using pluggable
+// transport, for example to self-hosted Sentry or to local file:
+//
+//  transport, err := sentryx.NewHTTPTransport("https://key@sentry.example.com/1")
+//
+//  exporter := sentryx.New(transport).SetRelease("1.0.0").SetInApp("example.com/app")
+//
+//  id, err := exporter.Export(ctx, err)
+package sentryx
@@ -0,0 +1,123 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryx
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// These constants define values of Sentry event and envelope.
+const (
+	Platform    = "go"
+	LevelError  = "error"
+	SDKName     = "rterror.sentryx"
+	SDKVersion  = "1.0.0"
+	ContentType = "application/x-sentry-envelope"
+)
+
+// Event defines a Sentry event with subset of fields used by exporter.
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Exception   *Exceptions       `json:"exception,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	SDK         *SDK              `json:"sdk,omitempty"`
+}
+
+// Exceptions defines a list of exception values. The last exception value
+// is the outermost error and the first one is the root cause.
+type Exceptions struct {
+	Values []*Exception `json:"values"`
+}
+
+// Exception defines a Sentry exception value created from single error.
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Module     string      `json:"module,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace defines a Sentry stack trace with frames from outermost to innermost call.
+type Stacktrace struct {
+	Frames []*Frame `json:"frames"`
+}
+
+// Frame defines a Sentry stack frame.
+type Frame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// SDK defines a Sentry SDK information.
+type SDK struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type envelopeHeader struct {
+	EventID string    `json:"event_id"`
+	SentAt  time.Time `json:"sent_at"`
+	DSN     string    `json:"dsn,omitempty"`
+}
+
+type itemHeader struct {
+	Type   string `json:"type"`
+	Length int    `json:"length"`
+}
+
+// WriteEnvelope writes event to writer as Sentry envelope with envelope
+// header, event item header and event payload separated by new lines.
+func (e *Event) WriteEnvelope(writer io.Writer, dsn string, sentAt time.Time) error {
+	payload, err := json.Marshal(e)
+
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+
+	if err := encoder.Encode(&envelopeHeader{
+		EventID: e.EventID,
+		SentAt:  sentAt.UTC(),
+		DSN:     dsn,
+	}); err != nil {
+		return err
+	}
+
+	if err := encoder.Encode(&itemHeader{
+		Type:   "event",
+		Length: len(payload),
+	}); err != nil {
+		return err
+	}
+
+	_, err = writer.Write(append(payload, '\n'))
+
+	return err
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// Exporter defines an exporter that converts error chains into Sentry events
+// and sends them using transport.
+type Exporter struct {
+	transport   Transport
+	release     string
+	environment string
+	serverName  string
+	inApp       []string
+}
+
+// runtimeError defines an interface of runtime error methods. It is
+// implemented by the RuntimeError type and by custom types that embed it.
+type runtimeError interface {
+	error
+	String() string
+	Package() string
+	Function() string
+	FunctionBase() string
+	File() string
+	FileBase() string
+	Line() int
+	GetKind() rterror.Kind
+	GetFields() map[string]interface{}
+	Fingerprint() string
+	Time() time.Time
+}
+
+// New creates a new exporter object that sends events using provided transport.
+// By default, frames from main module packages are marked as in-app frames.
+func New(transport Transport) *Exporter {
+	e := &Exporter{
+		transport: transport,
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && (info.Main.Path != "") {
+		e.inApp = []string{info.Main.Path}
+	}
+
+	return e
+}
+
+// SetRelease sets release of events.
+func (e *Exporter) SetRelease(release string) *Exporter {
+	e.release = release
+	return e
+}
+
+// GetRelease returns release of events.
+func (e *Exporter) GetRelease() string {
+	return e.release
+}
+
+// SetEnvironment sets environment of events.
+func (e *Exporter) SetEnvironment(environment string) *Exporter {
+	e.environment = environment
+	return e
+}
+
+// GetEnvironment returns environment of events.
+func (e *Exporter) GetEnvironment() string {
+	return e.environment
+}
+
+// SetServerName sets server name of events.
+func (e *Exporter) SetServerName(serverName string) *Exporter {
+	e.serverName = serverName
+	return e
+}
+
+// GetServerName returns server name of events.
+func (e *Exporter) GetServerName() string {
+	return e.serverName
+}
+
+// SetInApp sets module or package paths of in-app frames. Frame is in-app if
+// its package path is equal to or it is nested in any of provided paths.
+func (e *Exporter) SetInApp(paths ...string) *Exporter {
+	e.inApp = paths
+	return e
+}
+
+// GetInApp returns module or package paths of in-app frames.
+func (e *Exporter) GetInApp() []string {
+	return e.inApp
+}
+
+// Event converts error chain into Sentry event. Each error from error chain
+// becomes exception value with the root cause as the first one. Fields of
+// runtime errors become tags, outer errors override fields of inner errors.
+// Fingerprints of runtime errors become event fingerprint. It returns nil for nil error.
+func (e *Exporter) Event(err error) *Event {
+	if err == nil {
+		return nil
+	}
+
+	event := &Event{
+		EventID:     newEventID(),
+		Timestamp:   rterror.GetClock()().UTC(),
+		Platform:    Platform,
+		Level:       LevelError,
+		Release:     e.release,
+		Environment: e.environment,
+		ServerName:  e.serverName,
+		Exception:   &Exceptions{},
+		Tags:        make(map[string]string),
+		SDK: &SDK{
+			Name:    SDKName,
+			Version: SDKVersion,
+		},
+	}
+
+	chain := rterror.Chain(err)
+	timestamped := false
+
+	for index := len(chain) - 1; index >= 0; index-- {
+		event.Exception.Values = append(event.Exception.Values, e.exception(chain[index]))
+	}
+
+	for index := len(chain) - 1; index >= 0; index-- {
+		r, ok := chain[index].(runtimeError)
+
+		if !ok {
+			continue
+		}
+
+		for key, value := range r.GetFields() {
+			event.Tags[key] = fmt.Sprint(value)
+		}
+
+		if kind := r.GetKind(); kind != "" {
+			event.Tags["kind"] = string(kind)
+		}
+	}
+
+	for _, err := range chain {
+		r, ok := err.(runtimeError)
+
+		if !ok {
+			continue
+		}
+
+		event.Fingerprint = append(event.Fingerprint, r.Fingerprint())
+
+		if t := r.Time(); !timestamped && !t.IsZero() {
+			event.Timestamp = t.UTC()
+			timestamped = true
+		}
+	}
+
+	return event
+}
+
+// Export converts error chain into Sentry event and sends it using transport.
+// It returns event ID. Nothing is sent for nil error.
+func (e *Exporter) Export(ctx context.Context, err error) (string, error) {
+	event := e.Event(err)
+
+	if event == nil {
+		return "", nil
+	}
+
+	if err := e.transport.Send(ctx, event); err != nil {
+		return "", rterror.New("cannot send event {p0}", event.EventID).Wrap(err)
+	}
+
+	return event.EventID, nil
+}
+
+func (e *Exporter) exception(err error) *Exception {
+	r, ok := err.(runtimeError)
+
+	if !ok {
+		return &Exception{
+			Type:  fmt.Sprintf("%T", err),
+			Value: err.Error(),
+		}
+	}
+
+	exception := &Exception{
+		Type:   fmt.Sprintf("%T", err),
+		Value:  r.String(),
+		Module: r.Package(),
+		Stacktrace: &Stacktrace{
+			Frames: []*Frame{{
+				Function: r.FunctionBase(),
+				Module:   r.Package(),
+				Filename: r.FileBase(),
+				AbsPath:  r.File(),
+				Lineno:   r.Line(),
+				InApp:    e.isInApp(r.Package()),
+			}},
+		},
+	}
+
+	if kind := r.GetKind(); kind != "" {
+		exception.Type = string(kind)
+	}
+
+	return exception
+}
+
+func (e *Exporter) isInApp(module string) bool {
+	for _, path := range e.inApp {
+		if (module == path) || strings.HasPrefix(module, path+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newEventID returns random event ID as 32 hexadecimal characters.
+func newEventID() string {
+	var id [16]byte
+
+	_, _ = rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
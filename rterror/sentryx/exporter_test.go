@@ -0,0 +1,125 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/sentryx"
+)
+
+type recorder struct {
+	events []*sentryx.Event
+	err    error
+}
+
+func (r *recorder) Send(_ context.Context, event *sentryx.Event) error {
+	r.events = append(r.events, event)
+	return r.err
+}
+
+func TestExporterEvent(test *testing.T) {
+	root := errors.New("connection refused")
+	inner := rterror.New("cannot query {p0}", "users").SetKind("unavailable").
+		SetFields(map[string]interface{}{"db": "main", "password": "secret", "retry": 1}).Wrap(root)
+	outer := rterror.New("cannot get user").SetField("retry", 2).Wrap(fmt.Errorf("query: %w", inner))
+
+	exporter := sentryx.New(nil).SetRelease("1.0.0").SetEnvironment("test").SetServerName("host").
+		SetInApp("gitlab.com/tymonx/go-error")
+
+	event := exporter.Event(outer)
+	values := event.Exception.Values
+
+	assert.Len(test, event.EventID, 32)
+	assert.Equal(test, sentryx.Platform, event.Platform)
+	assert.Equal(test, sentryx.LevelError, event.Level)
+	assert.Equal(test, "1.0.0", event.Release)
+	assert.Equal(test, "test", event.Environment)
+	assert.Equal(test, "host", event.ServerName)
+	assert.Equal(test, []string{outer.Fingerprint(), inner.Fingerprint()}, event.Fingerprint)
+	assert.Equal(test, map[string]string{
+		"db":       "main",
+		"password": rterror.RedactedValue,
+		"retry":    "2",
+		"kind":     "unavailable",
+	}, event.Tags)
+
+	assert.Len(test, values, 4)
+	assert.Equal(test, &sentryx.Exception{Type: "*errors.errorString", Value: "connection refused"}, values[0])
+	assert.Equal(test, "unavailable", values[1].Type)
+	assert.Equal(test, "cannot query users", values[1].Value)
+	assert.Equal(test, "*fmt.wrapError", values[2].Type)
+	assert.Equal(test, "*rterror.RuntimeError", values[3].Type)
+	assert.Equal(test, "cannot get user", values[3].Value)
+	assert.Equal(test, "gitlab.com/tymonx/go-error/rterror/sentryx_test", values[3].Module)
+	assert.Equal(test, []*sentryx.Frame{{
+		Function: "TestExporterEvent",
+		Module:   "gitlab.com/tymonx/go-error/rterror/sentryx_test",
+		Filename: "exporter_test.go",
+		AbsPath:  outer.File(),
+		Lineno:   outer.Line(),
+		InApp:    true,
+	}}, values[3].Stacktrace.Frames)
+	assert.False(test, exporter.SetInApp("example.com/app").Event(outer).Exception.Values[3].Stacktrace.Frames[0].InApp)
+	assert.Equal(test, []string{"example.com/app"}, exporter.GetInApp())
+	assert.Nil(test, exporter.Event(nil))
+}
+
+func TestExporterTimestamp(test *testing.T) {
+	timestamp := time.Date(2021, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+	rterror.EnableTime()
+	rterror.SetClock(func() time.Time {
+		return timestamp
+	})
+
+	defer rterror.ResetClock()
+	defer rterror.DisableTime()
+
+	err := rterror.New("error")
+
+	rterror.SetClock(time.Now)
+
+	assert.Equal(test, timestamp, sentryx.New(nil).Event(err).Timestamp)
+}
+
+func TestExporterExport(test *testing.T) {
+	transport := &recorder{}
+	exporter := sentryx.New(transport)
+
+	id, err := exporter.Export(context.Background(), rterror.New("error"))
+
+	assert.NoError(test, err)
+	assert.Len(test, transport.events, 1)
+	assert.Equal(test, transport.events[0].EventID, id)
+
+	id, err = exporter.Export(context.Background(), nil)
+
+	assert.NoError(test, err)
+	assert.Empty(test, id)
+	assert.Len(test, transport.events, 1)
+
+	transport.err = errors.New("failed")
+
+	_, err = exporter.Export(context.Background(), rterror.New("error"))
+
+	assert.True(test, errors.Is(err, transport.err))
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+// These constants define values used by HTTP transport.
+const (
+	ProtocolVersion = "7"
+	ClientName      = SDKName + "/" + SDKVersion
+)
+
+// Transport defines an interface that sends Sentry events.
+type Transport interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+// FileTransport defines a transport that appends Sentry envelopes to file.
+// File can be sent later, for example with the sentry-cli send-envelope command.
+type FileTransport struct {
+	mutex sync.Mutex
+	path  string
+}
+
+// HTTPTransport defines a transport that sends Sentry envelopes to the envelope
+// endpoint of Sentry server with authentication from DSN.
+type HTTPTransport struct {
+	client *http.Client
+	dsn    string
+	url    string
+	key    string
+}
+
+// NewFileTransport creates a new file transport object that appends Sentry envelopes to file.
+func NewFileTransport(path string) *FileTransport {
+	return &FileTransport{
+		path: path,
+	}
+}
+
+// NewHTTPTransport creates a new HTTP transport object from Sentry DSN in
+// the form of scheme://key@host[:port][/path]/project. Returned errors never
+// contain DSN, it is wrapped with rterror.NewSecret because it contains key.
+func NewHTTPTransport(dsn string) (*HTTPTransport, error) {
+	parsed, err := url.Parse(dsn)
+
+	if err != nil {
+		var urlErr *url.Error
+
+		// The url.Error contains parsed DSN
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return nil, rterror.New("invalid Sentry DSN {p0}", rterror.NewSecret(dsn)).Wrap(err)
+	}
+
+	index := strings.LastIndexByte(parsed.Path, '/')
+
+	if (parsed.User == nil) || (parsed.User.Username() == "") || (index == -1) || (index == len(parsed.Path)-1) {
+		return nil, rterror.New("invalid Sentry DSN {p0}", rterror.NewSecret(dsn))
+	}
+
+	endpoint := url.URL{
+		Scheme: parsed.Scheme,
+		Host:   parsed.Host,
+		Path:   parsed.Path[:index] + "/api/" + parsed.Path[index+1:] + "/envelope/",
+	}
+
+	return &HTTPTransport{
+		client: http.DefaultClient,
+		dsn:    dsn,
+		url:    endpoint.String(),
+		key:    parsed.User.Username(),
+	}, nil
+}
+
+// Send appends event as Sentry envelope to file.
+func (f *FileTransport) Send(_ context.Context, event *Event) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+
+	if err != nil {
+		return rterror.New("cannot open envelope file {p0}", f.path).Wrap(err)
+	}
+
+	if err := event.WriteEnvelope(file, "", rterror.GetClock()()); err != nil {
+		_ = file.Close()
+		return rterror.New("cannot write envelope file {p0}", f.path).Wrap(err)
+	}
+
+	return file.Close()
+}
+
+// SetClient sets HTTP client used to send events.
+func (h *HTTPTransport) SetClient(client *http.Client) *HTTPTransport {
+	h.client = client
+	return h
+}
+
+// GetClient returns HTTP client used to send events.
+func (h *HTTPTransport) GetClient() *http.Client {
+	return h.client
+}
+
+// URL returns URL of Sentry envelope endpoint.
+func (h *HTTPTransport) URL() string {
+	return h.url
+}
+
+// Send sends event as Sentry envelope to Sentry server.
+func (h *HTTPTransport) Send(ctx context.Context, event *Event) error {
+	var body bytes.Buffer
+
+	if err := event.WriteEnvelope(&body, h.dsn, rterror.GetClock()()); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, &body)
+
+	if err != nil {
+		return rterror.New("cannot create request").Wrap(err)
+	}
+
+	request.Header.Set("Content-Type", ContentType)
+	request.Header.Set("X-Sentry-Auth", "Sentry sentry_version="+ProtocolVersion+
+		", sentry_client="+ClientName+", sentry_key="+h.key)
+
+	response, err := h.client.Do(request)
+
+	if err != nil {
+		return rterror.New("cannot send request").Wrap(err)
+	}
+
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if (response.StatusCode < http.StatusOK) || (response.StatusCode >= http.StatusMultipleChoices) {
+		return rterror.New("Sentry server responded with status {p0}", response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sentryx_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+	"gitlab.com/tymonx/go-error/rterror/sentryx"
+)
+
+type envelope struct {
+	Header struct {
+		EventID string `json:"event_id"`
+		DSN     string `json:"dsn"`
+	}
+	Item struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	Event   sentryx.Event
+	Payload []byte
+}
+
+func readEnvelopes(test *testing.T, reader io.Reader) (envelopes []*envelope) {
+	scanner := bufio.NewScanner(reader)
+
+	for scanner.Scan() {
+		e := &envelope{}
+
+		assert.NoError(test, json.Unmarshal(scanner.Bytes(), &e.Header))
+		assert.True(test, scanner.Scan())
+		assert.NoError(test, json.Unmarshal(scanner.Bytes(), &e.Item))
+		assert.True(test, scanner.Scan())
+
+		e.Payload = append([]byte(nil), scanner.Bytes()...)
+
+		assert.NoError(test, json.Unmarshal(e.Payload, &e.Event))
+
+		envelopes = append(envelopes, e)
+	}
+
+	return envelopes
+}
+
+func TestHTTPTransport(test *testing.T) {
+	var (
+		path   string
+		header http.Header
+		body   []byte
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		path, header = request.URL.Path, request.Header
+		body, _ = io.ReadAll(request.Body)
+	}))
+	defer server.Close()
+
+	dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/sentry/42"
+	transport, err := sentryx.NewHTTPTransport(dsn)
+
+	assert.NoError(test, err)
+	assert.Equal(test, server.URL+"/sentry/api/42/envelope/", transport.URL())
+	assert.Equal(test, http.DefaultClient, transport.SetClient(server.Client()).SetClient(http.DefaultClient).GetClient())
+
+	id, err := sentryx.New(transport).Export(context.Background(), rterror.New("error {p0}", 1))
+
+	assert.NoError(test, err)
+	assert.Equal(test, "/sentry/api/42/envelope/", path)
+	assert.Equal(test, sentryx.ContentType, header.Get("Content-Type"))
+	assert.Equal(test, "Sentry sentry_version=7, sentry_client=rterror.sentryx/1.0.0, sentry_key=public",
+		header.Get("X-Sentry-Auth"))
+
+	envelopes := readEnvelopes(test, bytes.NewReader(body))
+
+	assert.Len(test, envelopes, 1)
+	assert.Equal(test, id, envelopes[0].Header.EventID)
+	assert.Equal(test, dsn, envelopes[0].Header.DSN)
+	assert.Equal(test, "event", envelopes[0].Item.Type)
+	assert.Equal(test, len(envelopes[0].Payload), envelopes[0].Item.Length)
+	assert.Equal(test, "error 1", envelopes[0].Event.Exception.Values[0].Value)
+}
+
+func TestHTTPTransportStatus(test *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	transport, err := sentryx.NewHTTPTransport(strings.Replace(server.URL, "://", "://key@", 1) + "/1")
+
+	assert.NoError(test, err)
+
+	_, err = sentryx.New(transport).Export(context.Background(), rterror.New("error"))
+
+	assert.Error(test, err)
+	assert.Contains(test, err.Error(), "429")
+}
+
+func TestHTTPTransportDSN(test *testing.T) {
+	for _, dsn := range []string{"://key@", "https://sentry.example.com/1", "https://key@sentry.example.com", "https://key@sentry.example.com/"} {
+		_, err := sentryx.NewHTTPTransport(dsn)
+
+		assert.Error(test, err, dsn)
+
+		if err != nil {
+			assert.Contains(test, err.Error(), rterror.RedactedValue, dsn)
+			assert.NotContains(test, err.Error(), "key@", dsn)
+		}
+	}
+}
+
+func TestFileTransport(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "events.envelope")
+	exporter := sentryx.New(sentryx.NewFileTransport(path))
+
+	first, err := exporter.Export(context.Background(), rterror.New("first"))
+	assert.NoError(test, err)
+
+	second, err := exporter.Export(context.Background(), rterror.New("second"))
+	assert.NoError(test, err)
+
+	file, err := os.Open(path)
+	assert.NoError(test, err)
+
+	defer file.Close()
+
+	envelopes := readEnvelopes(test, file)
+
+	assert.Len(test, envelopes, 2)
+	assert.Equal(test, first, envelopes[0].Event.EventID)
+	assert.Equal(test, second, envelopes[1].Event.EventID)
+	assert.Equal(test, "second", envelopes[1].Event.Exception.Values[0].Value)
+
+	_, err = sentryx.New(sentryx.NewFileTransport(test.TempDir())).Export(context.Background(), rterror.New("error"))
+
+	assert.Error(test, err)
+}
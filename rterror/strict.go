@@ -0,0 +1,189 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"gitlab.com/tymonx/go-formatter/formatter"
+)
+
+// These constants define environment variables that control format failures.
+const (
+	StrictEnv = "RTERROR_STRICT"
+	DebugEnv  = "RTERROR_DEBUG"
+)
+
+// FailureMode defines behavior when error message or format cannot be formatted.
+type FailureMode int32
+
+// These constants define supported failure modes.
+const (
+	// FailureFallback silently falls back to unformatted error message.
+	FailureFallback FailureMode = iota
+
+	// FailureReport invokes format failure hooks and falls back to unformatted error message.
+	FailureReport
+
+	// FailurePanic panics with the *FormatError error.
+	FailurePanic
+)
+
+// FailureHook defines a function invoked when error message or format of runtime error cannot be formatted.
+type FailureHook func(r *RuntimeError, err *FormatError)
+
+// FormatError defines an error reported when error message or format cannot be formatted.
+type FormatError struct {
+	Format string
+	Err    error
+}
+
+var (
+	gFailureMode  = int32(getFailureModeEnv())          // nolint: gochecknoglobals
+	gDebug        = boolToInt32(isEnvEnabled(DebugEnv)) // nolint: gochecknoglobals
+	gFailureHooks hookChain[FailureHook]                // nolint: gochecknoglobals
+)
+
+// SetFailureMode sets package-wide failure mode.
+func SetFailureMode(mode FailureMode) {
+	atomic.StoreInt32(&gFailureMode, int32(mode))
+}
+
+// GetFailureMode returns package-wide failure mode.
+func GetFailureMode() FailureMode {
+	return FailureMode(atomic.LoadInt32(&gFailureMode))
+}
+
+// ResetFailureMode resets package-wide failure mode to default value. It is
+// taken from the RTERROR_STRICT environment variable. Without it, it is
+// FailurePanic when built with the rterror_strict build tag or FailureFallback otherwise.
+func ResetFailureMode() {
+	SetFailureMode(getFailureModeEnv())
+}
+
+// OnFormatFailure registers hook invoked in the FailureReport mode every time
+// error message or format cannot be formatted. A panic from hook is recovered.
+// It returns a function that unregisters hook.
+func OnFormatFailure(hook FailureHook) (remove func()) {
+	return gFailureHooks.add(hook)
+}
+
+// SetDebug enables or disables debug mode. In debug mode, fallback error
+// message is annotated with the formatting error.
+func SetDebug(enabled bool) {
+	atomic.StoreInt32(&gDebug, boolToInt32(enabled))
+}
+
+// EnableDebug enables debug mode.
+func EnableDebug() {
+	SetDebug(true)
+}
+
+// DisableDebug disables debug mode.
+func DisableDebug() {
+	SetDebug(false)
+}
+
+// IsDebugEnabled returns true if debug mode is enabled. Otherwise, it returns false.
+// Default value is taken from the RTERROR_DEBUG environment variable.
+func IsDebugEnabled() bool {
+	return atomic.LoadInt32(&gDebug) != 0
+}
+
+// Validate returns error if error message cannot be formatted with error
+// arguments or if error format cannot be formatted. Otherwise, it returns nil.
+func (r *RuntimeError) Validate() error {
+	if _, err := r.messageFormatter().Format(r._message, r._arguments...); err != nil {
+		return &FormatError{
+			Format: r._message,
+			Err:    err,
+		}
+	}
+
+	if _, err := formatter.Format(r.format, r); err != nil {
+		return &FormatError{
+			Format: r.format,
+			Err:    err,
+		}
+	}
+
+	return nil
+}
+
+// Error returns format error message.
+func (f *FormatError) Error() string {
+	return "cannot format " + strconv.Quote(f.Format) + ": " + f.Err.Error()
+}
+
+// Unwrap returns formatter error.
+func (f *FormatError) Unwrap() error {
+	return f.Err
+}
+
+// failback handles format failure according to failure mode and returns fallback error message.
+func (r *RuntimeError) failback(format string, err error) string {
+	e := r.reportFailure(format, err)
+
+	if IsDebugEnabled() {
+		return r._message + " (" + e.Error() + ")"
+	}
+
+	return r._message
+}
+
+// reportFailure handles format failure according to failure mode and returns format error.
+func (r *RuntimeError) reportFailure(format string, err error) *FormatError {
+	e := &FormatError{
+		Format: format,
+		Err:    err,
+	}
+
+	switch GetFailureMode() {
+	case FailurePanic:
+		panic(e)
+	case FailureReport:
+		gFailureHooks.invoke(func(hook FailureHook) {
+			hook(r, e)
+		})
+	case FailureFallback:
+	}
+
+	return e
+}
+
+func getFailureModeEnv() FailureMode {
+	switch strings.TrimSpace(strings.ToLower(os.Getenv(StrictEnv))) {
+	case "1", "true", "on", "yes", "enable", "y", "panic":
+		return FailurePanic
+	case "report":
+		return FailureReport
+	case "0", "false", "off", "no", "disable", "n", "fallback":
+		return FailureFallback
+	default:
+		return defaultFailureMode
+	}
+}
+
+func isEnvEnabled(name string) bool {
+	switch strings.TrimSpace(strings.ToLower(os.Getenv(name))) {
+	case "1", "true", "on", "yes", "enable", "y":
+		return true
+	default:
+		return false
+	}
+}
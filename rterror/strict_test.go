@@ -0,0 +1,105 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+func setFallback(test *testing.T) {
+	rterror.SetFailureMode(rterror.FailureFallback)
+
+	test.Cleanup(rterror.ResetFailureMode)
+}
+
+func TestValidate(test *testing.T) {
+	var formatError *rterror.FormatError
+
+	assert.NoError(test, rterror.New("A {p0}", 3).Validate())
+
+	err := rterror.New("A {p1}", 3).Validate()
+
+	assert.True(test, errors.As(err, &formatError))
+	assert.Equal(test, "A {p1}", formatError.Format)
+
+	err = rterror.New("A").SetFormat("{.Invalid}").Validate()
+
+	assert.True(test, errors.As(err, &formatError))
+	assert.Equal(test, "{.Invalid}", formatError.Format)
+}
+
+func TestFailureFallback(test *testing.T) {
+	defer rterror.ResetFailureMode()
+
+	rterror.SetFailureMode(rterror.FailureFallback)
+
+	assert.Equal(test, rterror.FailureFallback, rterror.GetFailureMode())
+	assert.Equal(test, "A {p1}", rterror.New("A {p1}", 3).String())
+}
+
+func TestFailurePanic(test *testing.T) {
+	defer rterror.ResetFailureMode()
+
+	rterror.SetFailureMode(rterror.FailurePanic)
+
+	assert.PanicsWithError(test, rterror.New("A {p1}", 3).Validate().Error(), func() {
+		_ = rterror.New("A {p1}", 3).String()
+	})
+
+	assert.Panics(test, func() {
+		_ = rterror.New("A").SetFormat("{.Invalid}").TopError()
+	})
+}
+
+func TestFailureReport(test *testing.T) {
+	var reported []*rterror.FormatError
+
+	defer rterror.ResetFailureMode()
+
+	remove := rterror.OnFormatFailure(func(r *rterror.RuntimeError, err *rterror.FormatError) {
+		reported = append(reported, err)
+	})
+
+	defer remove()
+
+	defer rterror.OnFormatFailure(func(r *rterror.RuntimeError, err *rterror.FormatError) {
+		panic(err)
+	})()
+
+	rterror.SetFailureMode(rterror.FailureReport)
+
+	assert.Equal(test, "A {p1}", rterror.New("A {p1}", 3).String())
+	assert.Len(test, reported, 1)
+	assert.Equal(test, "A {p1}", reported[0].Format)
+	assert.Error(test, errors.Unwrap(reported[0]))
+}
+
+func TestDebug(test *testing.T) {
+	setFallback(test)
+
+	defer rterror.DisableDebug()
+
+	rterror.EnableDebug()
+
+	err := rterror.New("A {p1}", 3)
+
+	assert.True(test, rterror.IsDebugEnabled())
+	assert.Equal(test, "A {p1} ("+err.Validate().Error()+")", err.String())
+	assert.Equal(test, "A {p0}", rterror.New("A {p0}").SetFormatter(rterror.SprintfFormatter{}).String())
+}
@@ -14,16 +14,10 @@
 
 package rterror
 
-import (
-	"errors"
-)
-
 // IsTemporary returns true if provided error is temporary. Otherwise, it returns false.
 func IsTemporary(err error) bool {
-	for ; err != nil; err = errors.Unwrap(err) {
-		if e, ok := err.(Temporarer); ok {
-			return e.Temporary()
-		}
+	if e, ok := Find[Temporarer](err); ok {
+		return e.Temporary()
 	}
 
 	return false
@@ -31,10 +25,8 @@ func IsTemporary(err error) bool {
 
 // IsTimeout returns true if provided error is a timeout. Otherwise, it returns false.
 func IsTimeout(err error) bool {
-	for ; err != nil; err = errors.Unwrap(err) {
-		if e, ok := err.(Timeouter); ok {
-			return e.Timeout()
-		}
+	if e, ok := Find[Timeouter](err); ok {
+		return e.Timeout()
 	}
 
 	return false
@@ -0,0 +1,154 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"reflect"
+)
+
+// MaxWalkDepth defines maximum depth of error chain visited by the Walk() function.
+// It protects against pathological chains that are too deep.
+const MaxWalkDepth = 1024
+
+// WalkFunc defines a function called for each error in error chain with its
+// depth. Provided error is at depth 0. Returning false stops walking.
+type WalkFunc func(depth int, err error) bool
+
+// Walk visits provided error and all errors in its chain in depth-first order.
+// It supports errors with the Unwrap() error and the Unwrap() []error methods.
+// Already visited errors are skipped, so error chains with cycles are walked only once.
+// It returns false if walking was stopped by the callback. Otherwise, it returns true.
+func Walk(err error, callback WalkFunc) bool {
+	visited := make(map[interface{}]bool)
+
+	return walk(err, 0, visited, callback)
+}
+
+// Chain returns provided error and all errors in its chain in depth-first order.
+func Chain(err error) []error {
+	var chain []error
+
+	Walk(err, func(_ int, e error) bool {
+		chain = append(chain, e)
+		return true
+	})
+
+	return chain
+}
+
+// Root returns the last error in the error chain. For errors with multiple
+// wrapped errors, it follows the first wrapped error. It returns nil for nil error.
+func Root(err error) error {
+	visited := make(map[interface{}]bool)
+
+	for depth := 0; (err != nil) && (depth < MaxWalkDepth); depth++ {
+		if key, ok := visitKey(err); ok {
+			if visited[key] {
+				break
+			}
+
+			visited[key] = true
+		}
+
+		var next error
+
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			next = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			if errs := e.Unwrap(); len(errs) != 0 {
+				next = errs[0]
+			}
+		}
+
+		if next == nil {
+			break
+		}
+
+		err = next
+	}
+
+	return err
+}
+
+// Find returns the first error in the error chain that has type T.
+// Type T can be a concrete error type or an interface type.
+func Find[T any](err error) (found T, ok bool) {
+	Walk(err, func(_ int, e error) bool {
+		found, ok = e.(T)
+		return !ok
+	})
+
+	return found, ok
+}
+
+// FindAll returns all errors in the error chain that have type T.
+func FindAll[T any](err error) (all []T) {
+	Walk(err, func(_ int, e error) bool {
+		if found, ok := e.(T); ok {
+			all = append(all, found)
+		}
+
+		return true
+	})
+
+	return all
+}
+
+// RuntimeErrors returns all runtime errors in the error chain.
+func RuntimeErrors(err error) []*RuntimeError {
+	return FindAll[*RuntimeError](err)
+}
+
+func walk(err error, depth int, visited map[interface{}]bool, callback WalkFunc) bool {
+	if (err == nil) || (depth >= MaxWalkDepth) {
+		return true
+	}
+
+	if key, ok := visitKey(err); ok {
+		if visited[key] {
+			return true
+		}
+
+		visited[key] = true
+	}
+
+	if !callback(depth, err) {
+		return false
+	}
+
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(e.Unwrap(), depth+1, visited, callback)
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range e.Unwrap() {
+			if !walk(wrapped, depth+1, visited, callback) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// visitKey returns key used to detect already visited errors. Cycles can be
+// created only with pointers, other errors are limited by MaxWalkDepth.
+func visitKey(err error) (interface{}, bool) {
+	if reflect.TypeOf(err).Kind() != reflect.Ptr {
+		return nil, false
+	}
+
+	return err, true
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+type cycleError struct {
+	next error
+}
+
+func (e *cycleError) Error() string {
+	return "cycle"
+}
+
+func (e *cycleError) Unwrap() error {
+	return e.next
+}
+
+func TestWalk(test *testing.T) {
+	c := rterror.New("C")
+	b := fmt.Errorf("B: %w", c)
+	a := rterror.New("A").Wrap(b)
+
+	var depths []int
+
+	var errs []error
+
+	assert.True(test, rterror.Walk(a, func(depth int, err error) bool {
+		depths = append(depths, depth)
+		errs = append(errs, err)
+
+		return true
+	}))
+
+	assert.Equal(test, []int{0, 1, 2}, depths)
+	assert.Equal(test, []error{a, b, c}, errs)
+}
+
+func TestWalkStop(test *testing.T) {
+	count := 0
+
+	assert.False(test, rterror.Walk(rterror.New("A").Wrap(rterror.New("B")), func(int, error) bool {
+		count++
+		return false
+	}))
+
+	assert.Equal(test, 1, count)
+}
+
+func TestWalkMultiple(test *testing.T) {
+	b, c, d := rterror.New("B"), rterror.New("C"), rterror.New("D")
+	a := rterror.New("A").Wrap(errors.Join(b.Wrap(c), d))
+
+	chain := rterror.Chain(a)
+
+	assert.Len(test, chain, 5)
+	assert.Equal(test, []error{b, c, d}, chain[2:])
+}
+
+func TestWalkCycle(test *testing.T) {
+	a := &cycleError{}
+	a.next = &cycleError{next: a}
+
+	assert.Len(test, rterror.Chain(a), 2)
+	assert.Equal(test, a.next, rterror.Root(a))
+}
+
+func TestChainNil(test *testing.T) {
+	assert.Empty(test, rterror.Chain(nil))
+}
+
+func TestRoot(test *testing.T) {
+	assert.Nil(test, rterror.Root(nil))
+	assert.Equal(test, syscall.EAGAIN, rterror.Root(rterror.New("A").Wrap(rterror.New("B").Wrap(syscall.EAGAIN))))
+	assert.Equal(test, syscall.EINTR, rterror.Root(errors.Join(fmt.Errorf("x: %w", syscall.EINTR), syscall.EAGAIN)))
+}
+
+func TestFind(test *testing.T) {
+	b := rterror.New("B").Wrap(&fs.PathError{Op: "open", Path: "file", Err: syscall.ENOENT})
+
+	found, ok := rterror.Find[*fs.PathError](rterror.New("A").Wrap(b))
+
+	assert.True(test, ok)
+	assert.Equal(test, "file", found.Path)
+
+	errno, ok := rterror.Find[syscall.Errno](b)
+
+	assert.True(test, ok)
+	assert.Equal(test, syscall.ENOENT, errno)
+
+	_, ok = rterror.Find[*cycleError](b)
+
+	assert.False(test, ok)
+}
+
+func TestFindInterface(test *testing.T) {
+	found, ok := rterror.Find[rterror.Temporarer](rterror.New("A").Wrap(syscall.EAGAIN))
+
+	assert.True(test, ok)
+	assert.True(test, found.Temporary())
+}
+
+func TestFindAll(test *testing.T) {
+	err := rterror.New("A").Wrap(errors.Join(syscall.EAGAIN, rterror.New("B").Wrap(syscall.EINTR)))
+
+	assert.Equal(test, []syscall.Errno{syscall.EAGAIN, syscall.EINTR}, rterror.FindAll[syscall.Errno](err))
+}
+
+func TestRuntimeErrors(test *testing.T) {
+	c := rterror.New("C")
+	b := fmt.Errorf("B: %w", c)
+	a := rterror.New("A").Wrap(b)
+
+	assert.Equal(test, []*rterror.RuntimeError{a, c}, rterror.RuntimeErrors(a))
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// WriteBufferSize defines size of buffer used to stream rendered errors.
+const WriteBufferSize = 4096
+
+// ErrLimitExceeded is returned by the limit writer when write limit was exceeded.
+var ErrLimitExceeded = errors.New("write limit exceeded") // nolint: gochecknoglobals
+
+// LimitWriter defines a writer that writes up to the given number of bytes.
+// Bytes above the limit are dropped and the ErrLimitExceeded error is returned.
+type LimitWriter struct {
+	writer    io.Writer
+	remaining int64
+}
+
+type countWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+// NewLimitWriter creates a new limit writer object that writes up to limit bytes to writer.
+func NewLimitWriter(writer io.Writer, limit int64) *LimitWriter {
+	return &LimitWriter{
+		writer:    writer,
+		remaining: limit,
+	}
+}
+
+// Write writes data to underlying writer up to the limit.
+func (l *LimitWriter) Write(data []byte) (n int, err error) {
+	if l.remaining <= 0 {
+		return 0, ErrLimitExceeded
+	}
+
+	exceeded := int64(len(data)) > l.remaining
+
+	if exceeded {
+		data = data[:l.remaining]
+	}
+
+	n, err = l.writer.Write(data)
+	l.remaining -= int64(n)
+
+	if (err == nil) && exceeded {
+		err = ErrLimitExceeded
+	}
+
+	return n, err
+}
+
+// Remaining returns number of bytes that still can be written.
+func (l *LimitWriter) Remaining() int64 {
+	return l.remaining
+}
+
+// WriteTo streams rendered runtime error with all its wrapped errors to writer.
+// It produces the same output as the Error() method without building the whole
+// string in memory. It returns number of written bytes and the first write error.
+func (r *RuntimeError) WriteTo(writer io.Writer) (int64, error) {
+	counter := &countWriter{
+		writer: writer,
+	}
+
+	buffered := bufio.NewWriterSize(counter, WriteBufferSize)
+
+	if err := r.GetRenderer().Render(buffered, r); err != nil {
+		return counter.count, err
+	}
+
+	err := buffered.Flush()
+
+	return counter.count, err
+}
+
+// WriteLimit streams rendered runtime error to writer like the WriteTo() method
+// but it writes up to limit bytes. The ErrLimitExceeded error is returned if
+// rendered runtime error was truncated.
+func (r *RuntimeError) WriteLimit(writer io.Writer, limit int64) (int64, error) {
+	return r.WriteTo(NewLimitWriter(writer, limit))
+}
+
+// WriteError streams rendered error to writer. Runtime errors are streamed
+// using the WriteTo() method, other errors are written using the Error() method.
+// Nothing is written for nil error.
+func WriteError(writer io.Writer, err error) (int64, error) {
+	if err == nil {
+		return 0, nil
+	}
+
+	if e, ok := err.(*RuntimeError); ok {
+		return e.WriteTo(writer)
+	}
+
+	n, e := io.WriteString(writer, err.Error())
+
+	return int64(n), e
+}
+
+func (c *countWriter) Write(data []byte) (int, error) {
+	n, err := c.writer.Write(data)
+	c.count += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 Tymoteusz Blazejczyk
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rterror_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/tymonx/go-error/rterror"
+)
+
+type failWriter struct {
+	remaining int
+}
+
+func (f *failWriter) Write(data []byte) (int, error) {
+	if len(data) > f.remaining {
+		n := f.remaining
+		f.remaining = 0
+
+		return n, syscall.EPIPE
+	}
+
+	f.remaining -= len(data)
+
+	return len(data), nil
+}
+
+func newDeepChain(depth int) *rterror.RuntimeError {
+	err := rterror.New("root")
+
+	for index := 0; index < depth; index++ {
+		err = rterror.New("error {p0}", index).Wrap(err)
+	}
+
+	return err
+}
+
+func TestWriteTo(test *testing.T) {
+	for _, style := range []rterror.Style{
+		rterror.StyleTree,
+		rterror.StyleOneLine,
+		rterror.StyleReverse,
+		rterror.StyleNumbered,
+	} {
+		var buffer bytes.Buffer
+
+		err := newDeepChain(100).SetRenderer(rterror.NewRenderer(style))
+		n, e := err.WriteTo(&buffer)
+
+		assert.NoError(test, e)
+		assert.Equal(test, int64(buffer.Len()), n)
+		assert.Equal(test, err.Error(), buffer.String(), "style %d", style)
+	}
+}
+
+func TestWriteToError(test *testing.T) {
+	writer := &failWriter{remaining: 10000}
+
+	n, err := newDeepChain(1000).WriteTo(writer)
+
+	assert.Equal(test, syscall.EPIPE, err)
+	assert.Equal(test, int64(10000), n)
+}
+
+func TestWriteLimit(test *testing.T) {
+	var buffer bytes.Buffer
+
+	err := newDeepChain(1000)
+	n, e := err.WriteLimit(&buffer, 100)
+
+	assert.True(test, errors.Is(e, rterror.ErrLimitExceeded))
+	assert.Equal(test, int64(100), n)
+	assert.Equal(test, err.Error()[:100], buffer.String())
+}
+
+func TestWriteLimitNotExceeded(test *testing.T) {
+	var buffer bytes.Buffer
+
+	err := rterror.New("error")
+	n, e := err.WriteLimit(&buffer, 1000)
+
+	assert.NoError(test, e)
+	assert.Equal(test, int64(len(err.Error())), n)
+}
+
+func TestLimitWriter(test *testing.T) {
+	var buffer strings.Builder
+
+	writer := rterror.NewLimitWriter(&buffer, 5)
+
+	n, err := writer.Write([]byte("abc"))
+
+	assert.NoError(test, err)
+	assert.Equal(test, 3, n)
+	assert.Equal(test, int64(2), writer.Remaining())
+
+	n, err = writer.Write([]byte("def"))
+
+	assert.Equal(test, rterror.ErrLimitExceeded, err)
+	assert.Equal(test, 2, n)
+
+	n, err = writer.Write([]byte("g"))
+
+	assert.Equal(test, rterror.ErrLimitExceeded, err)
+	assert.Zero(test, n)
+	assert.Equal(test, "abcde", buffer.String())
+}
+
+func TestWriteError(test *testing.T) {
+	var buffer bytes.Buffer
+
+	n, err := rterror.WriteError(&buffer, syscall.EAGAIN)
+
+	assert.NoError(test, err)
+	assert.Equal(test, int64(len(syscall.EAGAIN.Error())), n)
+
+	n, err = rterror.WriteError(&buffer, nil)
+
+	assert.NoError(test, err)
+	assert.Zero(test, n)
+
+	buffer.Reset()
+
+	e := newDeepChain(3)
+	_, err = rterror.WriteError(&buffer, e)
+
+	assert.NoError(test, err)
+	assert.Equal(test, e.Error(), buffer.String())
+}